@@ -1,9 +1,6 @@
 package cli
 
-import (
-	"fmt"
-	"strings"
-)
+import "time"
 
 // Args is a slice of args.
 type Args []option
@@ -28,8 +25,21 @@ type Arg[T Value] struct {
 	Layout   string // only applies to time.Time values
 	Value    *T
 	Required bool
+	Validate func(T) error // run after the value is parsed
+
+	// OnSet is called when the argument is set from the command line. An
+	// error stops parsing and is reported like an invalid value.
+	OnSet func(value T, source Source) error
+
+	Layouts  []string         // only applies to time.Time values; tried after Layout
+	Location *time.Location   // only applies to time.Time values
+	Now      func() time.Time // only applies to time.Time values; used for relative times
+
+	PathRequires PathRequirement // only applies to Path values
+	Extensions   []string        // only applies to Path values
+	Glob         bool            // only applies to []Path values
+	Expand       bool            // only applies to Path values; expands "~" and environment variables
 
-	isSlice    bool
 	hasBeenSet bool
 }
 
@@ -44,27 +54,53 @@ func (a *Arg[T]) Init() error {
 
 // Set parses the value of s and sets the value according to the arguments type.
 func (a *Arg[T]) Set(s string) error {
-	value, err := parseValue[T](s, ' ', a.Layout)
+	value, err := parseValue[T](s, a.parseOptions())
 	if err != nil {
 		return err
 	}
 
+	if a.Validate != nil {
+		if err := a.Validate(value); err != nil {
+			return err
+		}
+	}
+
 	*a.Value = value
-	a.isSlice = strings.HasPrefix(fmt.Sprint(value), "[")
 	a.hasBeenSet = true
 
+	if a.OnSet != nil {
+		return a.OnSet(value, SourceCommandLine)
+	}
+
 	return nil
 }
 
+func (a *Arg[T]) parseOptions() parseOptions {
+	return parseOptions{
+		separator: ' ',
+		time: timeOptions{
+			layouts:  timeLayouts(a.Layout, a.Layouts),
+			location: a.Location,
+			now:      a.Now,
+		},
+		path: pathOptions{
+			require:    a.PathRequires,
+			extensions: a.Extensions,
+			glob:       a.Glob,
+			expand:     a.Expand,
+		},
+	}
+}
+
 // String returns the string form of the arguments value.
 func (a *Arg[T]) String() string {
 	if a == nil || a.Value == nil {
 		return ""
 	}
 
-	// If the arg type is a slice, we have to remove the brackets that
-	// fmt.Sprint will add.
-	return trimBrackets(*a.Value)
+	// If the arg type is a slice, the values are joined with spaces (quoting
+	// where needed) so the string parses back to the same value.
+	return formatValue(*a.Value, ' ', a.parseOptions().time)
 }
 
 // Options returns the common Options available to both flags and arguments.
@@ -72,10 +108,11 @@ func (a *Arg[T]) Options() Options {
 	t := *new(T)
 
 	return Options{
-		IsSlice:    a.isSlice,
+		IsSlice:    isSliceValue[T](),
 		Name:       a.Name,
 		Desc:       a.Desc,
 		Layout:     a.Layout,
+		Type:       typeName[T](),
 		Default:    t,
 		Value:      a.Value,
 		Required:   a.Required,
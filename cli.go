@@ -1,18 +1,46 @@
 package cli
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
 	"unicode"
 )
 
-// ValueOf looks up the name of a flag and returns the value that it was set
-// to. It's main use should be in the SetOptions method.
+// ValueOf looks up the name of a flag and returns its current value, wherever
+// it came from: the command line, EnvVar, Default or DefaultFunc. If the flag
+// has a DefaultFunc that hasn't been called yet, it's called first. It's main
+// use should be in the SetOptions method, DefaultFunc and constraints.
+//
+// Before constraints were added, ValueOf returned the zero value unless the
+// flag was set on the command line. Use Options().HasBeenSet to tell whether
+// a value was set explicitly.
+//
+// The zero value is returned if there's no flag with the name or it isn't a
+// Flag[T]. Use JSONValueOf for a JSONFlag.
 func ValueOf[T Value](flags Flags, name string) T {
 	option := flags.Lookup(name)
-	if option != nil && option.Options().HasBeenSet {
-		flag := option.(*Flag[T])
+	if option == nil {
+		return *new(T)
+	}
+
+	if d, ok := option.(defaulter); ok {
+		// Errors are reported when the command resolves its own defaults.
+		_ = d.resolveDefault(flags)
+	}
+
+	flag, ok := option.(*Flag[T])
+	if ok && flag.Value != nil {
+		return *flag.Value
+	}
+
+	return *new(T)
+}
+
+// JSONValueOf is ValueOf for a JSONFlag. The zero value is returned if there's
+// no flag with the name or it isn't a JSONFlag[T].
+func JSONValueOf[T any](flags Flags, name string) T {
+	flag, ok := flags.Lookup(name).(*JSONFlag[T])
+	if ok && flag.Value != nil {
 		return *flag.Value
 	}
 
@@ -29,18 +57,62 @@ func visit(fn func(*Command) error, commands []*Command) error {
 	return nil
 }
 
+// isFlag returns true if arg looks like a flag. A lone "-" is a value that's
+// commonly used to mean stdin or stdout.
 func isFlag(arg string) bool {
-	return strings.HasPrefix(arg, "-")
+	return len(arg) > 1 && strings.HasPrefix(arg, "-")
 }
 
 func trimDash(s string) string {
 	return strings.TrimLeft(s, "-")
 }
 
+// flagName returns the long form of a flag if it has one, otherwise the
+// shorthand.
+func flagName(opt Options) string {
+	if opt.Name != "" {
+		return fmt.Sprintf("--%s", opt.Name)
+	}
+
+	return fmt.Sprintf("-%s", opt.Shorthand)
+}
+
+// envVarName returns the name of the environment variable an option reads
+// from, or "" if it doesn't read from one.
+func envVarName(opt Options) string {
+	switch v := opt.EnvVar.(type) {
+	case string:
+		return v
+	case interface{ envName() string }:
+		return v.envName()
+	}
+
+	return ""
+}
+
+// placeholder returns the placeholder for the value of a flag shown in help
+// (e.g. "<string>" or "<string>[,...]"). Bool flags don't take a value, so
+// they don't have one.
+func placeholder(opt Options) string {
+	if opt.Type == "" || (opt.Type == "bool" && !opt.IsSlice) {
+		return ""
+	}
+
+	if opt.IsSlice && opt.Separator != 0 {
+		return fmt.Sprintf("<%s>[%c...]", opt.Type, opt.Separator)
+	}
+
+	return fmt.Sprintf("<%s>", opt.Type)
+}
+
 func matchesFlag(arg string, opt option) bool {
 	o := opt.Options()
 	flag := trimDash(arg)
 
+	if flag == "" {
+		return false
+	}
+
 	if o.Name != flag && o.Shorthand != flag {
 		return false
 	}
@@ -61,20 +133,93 @@ func lastIndex(s, substr string) int {
 	return strings.LastIndex(tmp, substr)
 }
 
-func splitBytes(s []byte, sep byte) [][]byte {
+func splitBytes(s []byte, sep byte) ([][]byte, error) {
+	values, err := splitString(string(s), sep)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b = append(b, []byte(v))
+	}
+
+	return b, nil
+}
+
+// splitString splits s on sep the way a line of a CSV file is split. A value
+// can be wrapped in double quotes to include sep, and a double quote inside a
+// quoted value is escaped by doubling it (e.g. "say ""hi"", bye"). Outside of
+// quotes, sep and double quotes can also be escaped with a backslash (e.g.
+// a\,b). Any other backslash is kept as-is so paths on Windows don't need
+// escaping.
+func splitString(s string, sep byte) ([]string, error) {
 	if sep == 0 {
-		return [][]byte{s}
+		return []string{s}, nil
+	}
+
+	values := make([]string, 0)
+
+	var sb strings.Builder
+
+	quoted := false
+	wasQuoted := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case quoted && c == '"':
+			if i+1 < len(s) && s[i+1] == '"' {
+				sb.WriteByte('"')
+				i++
+				continue
+			}
+
+			quoted = false
+		case quoted:
+			sb.WriteByte(c)
+		case c == '"' && sb.Len() == 0 && !wasQuoted:
+			quoted = true
+			wasQuoted = true
+		case c == '\\' && i+1 < len(s) && (s[i+1] == sep || s[i+1] == '"'):
+			sb.WriteByte(s[i+1])
+			i++
+		case c == sep:
+			values = append(values, sb.String())
+			sb.Reset()
+			wasQuoted = false
+		default:
+			sb.WriteByte(c)
+		}
 	}
 
-	return bytes.Split(s, []byte{sep})
+	if quoted {
+		return nil, fmt.Errorf("missing closing quote in %q", s)
+	}
+
+	values = append(values, sb.String())
+
+	return values, nil
 }
 
-func splitString(s string, sep byte) []string {
+// joinValues joins values with sep so that splitString returns the same
+// values. Values that contain sep, a double quote or a backslash are quoted.
+func joinValues(values []string, sep byte) string {
 	if sep == 0 {
-		return []string{s}
+		sep = ' '
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.IndexByte(v, sep) >= 0 || strings.ContainsAny(v, `"\`) {
+			v = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+		}
+
+		quoted = append(quoted, v)
 	}
 
-	return strings.Split(s, string(sep))
+	return strings.Join(quoted, string(sep))
 }
 
 func trimBrackets(s any) string {
@@ -88,6 +233,9 @@ func trimBrackets(s any) string {
 //
 // There's way too much variation in the way folks do this. Let me be clear: The
 // first letter in the description is capital and there is no period at the end.
+// Words that already have capitals (e.g. iOS), code spans and descriptions
+// with more than one sentence are left alone since changing them would make
+// them wrong.
 func formatDesc(s string) string {
 	var sb strings.Builder
 
@@ -95,8 +243,19 @@ func formatDesc(s string) string {
 		return s
 	}
 
-	sb.WriteRune(unicode.ToUpper(rune(s[0])))
-	sb.WriteString(strings.TrimSuffix(s[1:], "."))
+	first, _, _ := strings.Cut(s, " ")
+	if strings.IndexFunc(first, unicode.IsUpper) > 0 || strings.HasPrefix(s, "`") {
+		sb.WriteString(s[:1])
+	} else {
+		sb.WriteRune(unicode.ToUpper(rune(s[0])))
+	}
+
+	rest := s[1:]
+	if !strings.HasSuffix(rest, "..") && !strings.Contains(rest, ". ") {
+		rest = strings.TrimSuffix(rest, ".")
+	}
+
+	sb.WriteString(rest)
 
 	return sb.String()
 }
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCommand struct {
+	cmd *Command
+	run func() error
+}
+
+func (tc *testCommand) Init() *Command {
+	return tc.cmd
+}
+
+func (tc *testCommand) Run() error {
+	if tc.run == nil {
+		return nil
+	}
+
+	return tc.run()
+}
+
+// execute runs cmd as the root command with args after its name and returns
+// what it and its subcommands wrote. Output is captured unless cmd already
+// writes somewhere else. Commands can only be executed once.
+func execute(cmd *Command, args ...string) (string, error) {
+	return executeRunner(&testCommand{cmd: cmd}, args...)
+}
+
+// executeRunner is like execute, but for a runner that does something when
+// it's run.
+func executeRunner(tc *testCommand, args ...string) (string, error) {
+	var out bytes.Buffer
+
+	// Commands default to stdout once subcommands are added, and subcommands
+	// get the output of their parent.
+	_ = tc.cmd.Visit(func(c *Command) error {
+		if c.output == nil || c.output == os.Stdout {
+			c.output = &out
+		}
+
+		return nil
+	}, VisitDescendants)
+
+	err := Execute(tc, append([]string{tc.cmd.Name}, args...))
+
+	return out.String(), err
+}
+
+// stripANSI removes color codes from s.
+func stripANSI(s string) string {
+	var sb strings.Builder
+
+	escape := false
+
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			escape = r != 'm'
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func TestValueOf(t *testing.T) {
+	t.Setenv("CLI_TEST_REGION", "eu-west-1")
+
+	output := &Flag[string]{Name: "output", Default: "json"}
+	region := &Flag[string]{Name: "region", EnvVar: EnvVar[string]{Name: "CLI_TEST_REGION"}}
+	user := &Flag[string]{Name: "user", DefaultFunc: func(Flags) (string, error) { return "nobody", nil }}
+	count := &Flag[int]{Name: "count"}
+	flags := Flags{output, region, user, count}
+
+	for _, flag := range flags {
+		assert.NoError(t, flag.Init())
+	}
+
+	assert.NoError(t, count.Set("3"))
+
+	assert.Equal(t, "json", ValueOf[string](flags, "output"))
+	assert.Equal(t, "eu-west-1", ValueOf[string](flags, "region"))
+	assert.Equal(t, "nobody", ValueOf[string](flags, "user"))
+	assert.Equal(t, 3, ValueOf[int](flags, "count"))
+	assert.Equal(t, "", ValueOf[string](flags, "missing"))
+	assert.Equal(t, "", ValueOf[string](flags, "count"), "wrong type")
+
+	assert.False(t, output.Options().HasBeenSet)
+	assert.True(t, count.Options().HasBeenSet)
+}
+
+func TestSplitString(t *testing.T) {
+	testCases := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{"Plain", "a,b,c", []string{"a", "b", "c"}, false},
+		{"Quoted separator", `a,"b,c",d`, []string{"a", "b,c", "d"}, false},
+		{"Escaped quote", `"say ""hi""",bye`, []string{`say "hi"`, "bye"}, false},
+		{"Escaped separator", `a\,b,c`, []string{"a,b", "c"}, false},
+		{"Windows path", `C:\foo,C:\bar`, []string{`C:\foo`, `C:\bar`}, false},
+		{"Empty values", "a,,b", []string{"a", "", "b"}, false},
+		{"Missing closing quote", `a,"b`, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := splitString(tc.s, ',')
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+
+			again, err := splitString(joinValues(got, ','), ',')
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, again)
+		})
+	}
+
+	t.Run("Flag string round trip", func(t *testing.T) {
+		var names []string
+
+		flag := &Flag[[]string]{
+			Name:      "names",
+			Separator: ',',
+			Default:   []string{"a,b", `c "d"`, "e f"},
+			Value:     &names,
+		}
+
+		assert.NoError(t, flag.Init())
+		assert.Equal(t, []string{"a,b", `c "d"`, "e f"}, names)
+
+		s := flag.String()
+		assert.NoError(t, flag.Set(s))
+		assert.Equal(t, []string{"a,b", `c "d"`, "e f"}, names)
+	})
+
+	t.Run("Arg with spaces", func(t *testing.T) {
+		var names []string
+
+		_, err := execute(&Command{
+			Name: "test",
+			Args: Args{
+				&Arg[[]string]{Name: "names", Value: &names},
+			},
+		}, "a b", "c")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a b", "c"}, names)
+	})
+}
+
+func TestFormatDesc(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want string
+	}{
+		{"print help information.", "Print help information"},
+		{"iOS device to use", "iOS device to use"},
+		{"`kubectl` binary to use", "`kubectl` binary to use"},
+		{"Wait for it. Then go.", "Wait for it. Then go."},
+		{"And so on...", "And so on..."},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, formatDesc(tc.s))
+	}
+}
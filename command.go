@@ -11,11 +11,11 @@ import (
 	"github.com/rdeusser/cli/ast"
 	"github.com/rdeusser/cli/help"
 	"github.com/rdeusser/cli/internal/errors"
-	"github.com/rdeusser/cli/internal/join"
 	"github.com/rdeusser/cli/internal/multierror"
 	"github.com/rdeusser/cli/internal/slice"
+	"github.com/rdeusser/cli/internal/termenv"
 	"github.com/rdeusser/cli/parser"
-	"github.com/rdeusser/cli/tablewriter"
+	"github.com/rdeusser/cli/theme"
 )
 
 type VisitOption int
@@ -24,10 +24,36 @@ const (
 	VisitStartingAtChild VisitOption = iota
 	VisitStartingAtParent
 	VisitStartingAtChildReverse
+
+	// VisitDescendants visits the command and every command below it,
+	// parents before children, in the order they're shown in help.
+	VisitDescendants
 )
 
 type VisitFunc func(*Command) error
 
+// Example is an example of how a command is used.
+type Example struct {
+	// Command is the command line (e.g. "kubectl apply -f pod.json").
+	Command string
+
+	// Desc describes what the example does.
+	Desc string
+}
+
+// HelpTopic is a help page that isn't about a command (e.g. "environment" for
+// the environment variables a program reads). It's shown with "help <topic>".
+type HelpTopic struct {
+	// Name is the name of the topic (e.g. "config-format").
+	Name string
+
+	// Desc is the short description shown in the list of topics.
+	Desc string
+
+	// Text is the page. It uses the same markup as LongDesc.
+	Text string
+}
+
 // Command is a command. How else are you supposed to describe this?
 // e.g. `go run main.go`
 type Command struct {
@@ -37,7 +63,9 @@ type Command struct {
 	// Desc is the short description the command.
 	Desc string
 
-	// LongDesc is the long description of the command.
+	// LongDesc is the long description of the command. It can span many
+	// paragraphs and is dedented, so it can be an indented raw string. Bullet
+	// lists, code blocks and `code spans` are supported (see help.ParseText).
 	LongDesc string
 
 	// Flags is the full set of flags passed to the command.
@@ -47,9 +75,66 @@ type Command struct {
 	// processed.
 	Args Args
 
+	// Constraints are rules across flags (e.g. mutually exclusive flags) that
+	// are checked after all flags have been set.
+	Constraints []Constraint
+
+	// Group is the section of its parent's help the command is shown in
+	// (e.g. "Management Commands"). Commands without a group are shown under
+	// COMMANDS.
+	Group string
+
+	// KeepOrder shows flags and subcommands in help in the order they're
+	// declared instead of sorting them by name.
+	KeepOrder bool
+
+	// Examples show how the command is used. They're shown in help and
+	// generated docs.
+	Examples []Example
+
+	// HelpTopics are help pages that aren't about a command. They're listed
+	// in the help of the root command and shown with "help <topic>". They're
+	// only used on the root command.
+	HelpTopics []HelpTopic
+
+	// HelpRenderer renders the help for the command. It's inherited by
+	// subcommands that don't set their own.
+	HelpRenderer help.Renderer
+
+	// HelpTemplate is a text/template that renders the help for the command
+	// when HelpRenderer isn't set. It's executed with a help.Command and is
+	// inherited like HelpRenderer. See help.TemplateRenderer for the functions
+	// it can use.
+	HelpTemplate string
+
+	// Theme is the styles of help, warnings and errors. It's inherited by
+	// subcommands that don't set their own, and errors use the theme of the
+	// root command. theme.Default is used if no command sets one.
+	Theme *theme.Theme
+
+	// Pager pages help that doesn't fit on the terminal through $PAGER (or
+	// "less -R" if it isn't set). It adds --no-pager, and setting NO_PAGER
+	// also turns it off. It's only used on the root command.
+	Pager bool
+
+	// SpecCommand adds a hidden "__spec" command that prints the spec of the
+	// command tree as JSON (see Command.Spec). It's only used on the root
+	// command.
+	SpecCommand bool
+
 	// parent of the current command.
 	parent *Command
 
+	// order is the position the command was added to its parent in.
+	order int
+
+	// inherited are the persistent flags inherited from parents.
+	inherited Flags
+
+	// flagGroups are the groups of the command's flags in the order they're
+	// declared.
+	flagGroups []string
+
 	// commands is a map of command names to the commands command.
 	commands map[string]*Command
 
@@ -87,8 +172,18 @@ type Command struct {
 	// usage is the combined usage of commands, flags, and arguments.
 	usage string
 
+	// input is where InputFile values named "-" are read from.
+	input io.Reader
+
 	// output is where help and errors are written to.
 	output io.Writer
+
+	// noPager is true if --no-pager was passed to the root command.
+	noPager bool
+
+	// color is whether the output of the root command is colored. It's
+	// decided by Execute from --color; nil means it hasn't been decided.
+	color *bool
 }
 
 // AddCommands adds commands to the current command as children.
@@ -102,11 +197,53 @@ func (c *Command) AddCommands(runners ...Runner) {
 		cmd.parent = c
 		cmd.stmt = c.stmt
 		cmd.output = c.Output()
+		cmd.order = len(c.commands)
 
 		c.commands[cmd.Name] = cmd
 	}
 }
 
+// Parent returns the parent of the command, or nil for the root command.
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+// Commands returns the subcommands of the command in the order they're shown
+// in help. The built-in help command is left out, since it doesn't need docs of
+// its own.
+func (c *Command) Commands() []*Command {
+	commands := make([]*Command, 0, len(c.commands))
+
+	for _, cmd := range c.visibleCommands() {
+		if _, ok := cmd.runner.(*helpCommand); !ok {
+			commands = append(commands, cmd)
+		}
+	}
+
+	return commands
+}
+
+// visibleCommands returns the subcommands that are shown in help, which is all
+// of them except the __spec command.
+func (c *Command) visibleCommands() []*Command {
+	commands := make([]*Command, 0, len(c.commands))
+
+	for _, cmd := range c.getCommands() {
+		if _, ok := cmd.runner.(*specCommand); !ok {
+			commands = append(commands, cmd)
+		}
+	}
+
+	return commands
+}
+
+// HelpModel returns the structured help for the command, which is what help
+// renderers and doc generators work from. Commands from Prepare and Execute
+// have their inherited flags included.
+func (c *Command) HelpModel() help.Command {
+	return c.helpModel()
+}
+
 // Output returns the io.Writer that the command uses to write output to.
 func (c *Command) Output() io.Writer {
 	if c.output == nil {
@@ -121,6 +258,25 @@ func (c *Command) SetOutput(w io.Writer) {
 	c.output = w
 }
 
+// Input returns the io.Reader that the command uses to read input from. If it
+// hasn't been set, the parent's input is used.
+func (c *Command) Input() io.Reader {
+	if c.input != nil {
+		return c.input
+	}
+
+	if c.parent != nil {
+		return c.parent.Input()
+	}
+
+	return os.Stdin
+}
+
+// SetInput sets the io.Reader that the command uses to read input from.
+func (c *Command) SetInput(r io.Reader) {
+	c.input = r
+}
+
 // FullName returns the full name of the command starting from the root.
 func (c *Command) FullName() string {
 	commands := make([]string, 0)
@@ -159,9 +315,10 @@ func (c *Command) HasFlag(name, shorthand string) bool {
 	return true
 }
 
-// PrintHelp prints the command's help.
+// PrintHelp prints the command's help, through the pager if the root command
+// has Pager set.
 func (c *Command) PrintHelp() {
-	c.Output().Write([]byte(c.usage))
+	_ = c.printPaged(c.Output(), c.usage)
 }
 
 // Visit runs fn for each command starting from the top-most parent.
@@ -176,6 +333,8 @@ func (c *Command) Visit(fn VisitFunc, option VisitOption) error {
 		}
 
 		return visit(fn, commands)
+	case VisitDescendants:
+		return visit(fn, c.descendants())
 	default:
 		parent := c.parent
 		for parent != nil {
@@ -205,25 +364,21 @@ func (c *Command) Visit(fn VisitFunc, option VisitOption) error {
 // options, runs the runners, and checks for unknown and required
 // arguments/flags.
 func (c *Command) parseCommands(args []string) error {
-	c.init()
-
-	if err := c.addParentFlags(); err != nil {
+	if err := c.prepare(); err != nil {
 		return err
 	}
 
-	c.sortCommands()
-	c.sortFlags()
-	c.generateUsage()
+	if cmd, i := c.findCommand(args); cmd != nil {
+		return cmd.parseCommands(slice.Remove(args, i, i+1))
+	}
 
-	noFlags, err := c.parseFlags(args)
-	if err != nil {
+	if err := c.initOptions(); err != nil {
 		return c.errOrPrintHelp(err)
 	}
 
-	for _, arg := range noFlags {
-		if cmd, ok := c.commands[arg]; ok {
-			return cmd.parseCommands(noFlags)
-		}
+	noFlags, err := c.parseFlags(args)
+	if err != nil {
+		return c.errOrPrintHelp(err)
 	}
 
 	noFlagsOrArgs, err := c.parseArgs(noFlags)
@@ -235,10 +390,18 @@ func (c *Command) parseCommands(args []string) error {
 		return c.errOrPrintHelp(err)
 	}
 
+	if err := c.resolveDefaults(); err != nil {
+		return c.errOrPrintHelp(err)
+	}
+
 	if err := c.checkRequired(); err != nil {
 		return c.errOrPrintHelp(err)
 	}
 
+	if err := c.checkConstraints(); err != nil {
+		return c.errOrPrintHelp(err)
+	}
+
 	if c.optionSetter != nil {
 		if c.parent == nil {
 			return ErrMustHaveParent
@@ -249,6 +412,41 @@ func (c *Command) parseCommands(args []string) error {
 		}
 	}
 
+	c.bindFiles()
+
+	err = c.run()
+	if cerr := c.closeFiles(err == nil); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// prepare adds parent flags, sorts commands and flags, and generates the usage
+// string.
+func (c *Command) prepare() error {
+	c.init()
+
+	if c.parent != nil {
+		c.stmt = c.parent.stmt
+	}
+
+	if err := c.addParentFlags(); err != nil {
+		return err
+	}
+
+	if err := c.checkConstraintFlags(); err != nil {
+		return err
+	}
+
+	c.sortCommands()
+	c.sortFlags()
+
+	return c.generateUsage()
+}
+
+// run runs the runners in order. See the comments on Command for the order.
+func (c *Command) run() error {
 	if err := c.Visit(func(cmd *Command) error {
 		if cmd.persistentPreRunner != nil {
 			if err := cmd.persistentPreRunner.PersistentPreRun(); err != nil {
@@ -311,7 +509,9 @@ func (c *Command) init() {
 	}
 }
 
-// addParentFlags adds the flags the parent currently has to this command.
+// addParentFlags adds the persistent flags the parent currently has to this
+// command. A flag on this command with the same name as a persistent flag
+// shadows it.
 func (c *Command) addParentFlags() error {
 	var merr multierror.Error
 
@@ -319,21 +519,60 @@ func (c *Command) addParentFlags() error {
 		return nil
 	}
 
-	for _, flag := range c.Flags {
+	c.Flags = c.localFlags()
+	c.inherited = make(Flags, 0)
+
+	for _, flag := range c.parent.Flags {
 		opt := flag.Options()
-		if c.parent.HasFlag(opt.Name, opt.Shorthand) {
+		if !opt.Persistent {
+			continue
+		}
+
+		if opt.Name != "" && c.Flags.Lookup(opt.Name) != nil {
+			continue
+		}
+
+		if opt.Shorthand != "" && c.Flags.Lookup(opt.Shorthand) != nil {
 			merr.Append(ErrFlagAlreadyDefined{
 				Name:      opt.Name,
 				Shorthand: opt.Shorthand,
 			})
+
+			continue
 		}
+
+		c.inherited = append(c.inherited, flag)
 	}
 
-	c.Flags = append(c.Flags, c.parent.Flags...)
+	c.Flags = append(c.Flags, c.inherited...)
 
 	return merr.ErrorOrNil()
 }
 
+// localFlags returns the flags defined on the command itself.
+func (c *Command) localFlags() Flags {
+	flags := make(Flags, 0, len(c.Flags))
+
+	for _, flag := range c.Flags {
+		if !c.isInherited(flag) {
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags
+}
+
+// isInherited returns true if flag was inherited from a parent.
+func (c *Command) isInherited(flag option) bool {
+	for _, f := range c.inherited {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
 // setRunners sets thee
 func (c *Command) setRunners(runner Runner) {
 	if v, ok := runner.(OptionSetter); ok {
@@ -359,117 +598,229 @@ func (c *Command) setRunners(runner Runner) {
 	}
 }
 
+// initOptions initializes every flag and argument. Values from defaults and
+// environment variables are applied here so the command line can override them.
+func (c *Command) initOptions() error {
+	for _, flag := range c.Flags {
+		if v, ok := flag.(inputSetter); ok {
+			v.setInput(c.Input())
+		}
+
+		if err := flag.Init(); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range c.Args {
+		if err := arg.Init(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findCommand returns the first subcommand named in args along with its
+// position. Values given to flags are skipped so that a value that happens to
+// share a name with a subcommand isn't mistaken for it.
+func (c *Command) findCommand(args []string) (*Command, int) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if isFlag(arg) {
+			if c.Flags.lookupSecretFile(arg) != nil {
+				i++
+				continue
+			}
+
+			flag := c.Flags.Lookup(arg)
+			if flag == nil {
+				continue
+			}
+
+			if _, ok := flag.Options().Value.(*bool); !ok {
+				i++
+			}
+
+			continue
+		}
+
+		if cmd, ok := c.commands[arg]; ok {
+			return cmd, i
+		}
+	}
+
+	return nil, -1
+}
+
+// parseFlags sets the value of each flag found in args and returns the args
+// that weren't consumed.
 func (c *Command) parseFlags(args []string) ([]string, error) {
-	buf := args
+	buf := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 
-	for i, arg := range args {
 		if matchesFlag(arg, HelpFlag) {
 			return buf, ErrPrintHelp
 		}
 
 		if !isFlag(arg) {
+			buf = append(buf, arg)
 			continue
 		}
 
-		flag := c.Flags.Lookup(arg)
-		if flag == nil {
+		if flag := c.Flags.lookupSecretFile(arg); flag != nil {
+			if i+1 >= len(args) {
+				return buf, ErrFlagMissingValue{
+					Name: trimDash(arg),
+				}
+			}
+
+			i++
+
+			value, err := c.readSecretFile(args[i])
+			if err != nil {
+				return buf, c.invalidValue(arg, args[i], false, err)
+			}
+
+			if err := flag.Set(value); err != nil {
+				return buf, c.invalidValue(arg, args[i], false, redactError(err, value))
+			}
+
 			continue
 		}
 
-		if err := flag.Init(); err != nil {
-			return buf, err
+		flag := c.Flags.Lookup(arg)
+		if flag == nil {
+			buf = append(buf, arg)
+			continue
 		}
 
 		opt := flag.Options()
 		switch opt.Value.(type) {
 		case *bool:
 			if err := flag.Set("true"); err != nil {
-				return buf, err
+				return buf, c.invalidValue(arg, "true", false, err)
 			}
-
-			buf = slice.Remove(buf, i, i+1)
 		default:
 			if opt.IsSlice && opt.Separator == 0 {
 				return buf, ErrFlagSliceMustHaveSeparator
 			}
 
-			if err := flag.Set(args[i+1]); err != nil {
-				return buf, err
+			if i+1 >= len(args) {
+				return buf, ErrFlagMissingValue{
+					Name:      opt.Name,
+					Shorthand: opt.Shorthand,
+				}
 			}
 
-			buf = slice.Remove(buf, i, i+1)
+			i++
+
+			if err := flag.Set(args[i]); err != nil {
+				return buf, c.invalidValue(arg, args[i], opt.Secret, err)
+			}
 		}
 	}
 
 	return buf, nil
 }
 
+// parseArgs sets the value of each argument by its position in args and returns
+// the args that weren't consumed. An argument that holds many values consumes
+// the rest of the positional args.
 func (c *Command) parseArgs(args []string) ([]string, error) {
 	buf := make([]string, 0)
+	positional := slice.Reduce(args, func(a string) bool {
+		return !isFlag(a)
+	})
 
-	for i := range args {
+	buf = append(buf, slice.Reduce(args, isFlag)...)
+
+	for i := 0; i < len(positional); i++ {
 		arg := c.Args.Lookup(i)
 		if arg == nil {
+			buf = append(buf, positional[i])
 			continue
 		}
 
-		if err := arg.Init(); err != nil {
-			return buf, err
+		opt := arg.Options()
+		value := positional[i]
+
+		if opt.IsSlice {
+			value = joinValues(positional[i:], ' ')
 		}
 
-		buf = slice.Reduce(args[i:], func(a string) bool {
-			return !isFlag(a)
-		})
+		if err := arg.Set(value); err != nil {
+			return buf, c.invalidValue(fmt.Sprintf("<%s>", opt.Name), positional[i], false, err)
+		}
 
-		if err := arg.Set(join.Args(buf)); err != nil {
-			return buf, err
+		if opt.IsSlice {
+			break
 		}
 	}
 
 	return buf, nil
 }
 
+// checkUnknown returns an error for the first arg that wasn't consumed by a
+// flag or argument.
 func (c *Command) checkUnknown(args []string) error {
-	seen := make(map[string]struct{})
+	for _, arg := range args {
+		var start, end int
 
-	for _, cmd := range c.getCommands() {
-		seen[cmd.Name] = struct{}{}
+		if node := c.stmt.Lookup(arg); node != nil {
+			start, end = node.Pos()
+		}
+
+		return ErrUnknown{
+			Input:    c.redactedInput(),
+			Arg:      arg,
+			StartPos: start,
+			EndPos:   end,
+		}
 	}
 
-	for _, flag := range c.Flags {
-		opt := flag.Options()
-		shorthand := fmt.Sprintf("-%s", opt.Shorthand)
-		name := fmt.Sprintf("--%s", opt.Name)
+	return nil
+}
 
-		if opt.Shorthand != "" {
-			seen[shorthand] = struct{}{}
-		}
+// invalidValue wraps err with the flag or argument it came from and where the
+// value is in the input. Secret values are redacted.
+func (c *Command) invalidValue(option, value string, secret bool, err error) error {
+	var start, end int
 
-		if opt.Name != "" {
-			seen[name] = struct{}{}
-		}
+	if node := c.stmt.Lookup(value); node != nil {
+		start, end = node.Pos()
+	}
 
-		seen[flag.String()] = struct{}{}
+	if secret {
+		err = redactError(err, value)
+		value = redacted
+		end = start + len(redacted) + 1
 	}
 
-	for _, arg := range c.Args {
-		seen[arg.String()] = struct{}{}
+	return ErrInvalidValue{
+		Option:   option,
+		Value:    value,
+		Input:    c.redactedInput(),
+		StartPos: start,
+		EndPos:   end,
+		Err:      err,
 	}
+}
 
-	for _, arg := range args {
-		if _, ok := seen[arg]; !ok {
-			start, end := c.stmt.Lookup(arg).Pos()
-
-			return ErrUnknown{
-				Input:    c.stmt.String(),
-				Arg:      arg,
-				StartPos: start,
-				EndPos:   end,
-			}
+// resolveDefaults computes the defaults of flags that weren't set on the
+// command line or by an environment variable.
+func (c *Command) resolveDefaults() error {
+	var merr multierror.Error
+
+	for _, flag := range c.Flags {
+		if d, ok := flag.(defaulter); ok {
+			merr.Append(d.resolveDefault(c.Flags))
 		}
 	}
 
-	return nil
+	return merr.ErrorOrNil()
 }
 
 func (c *Command) checkRequired() error {
@@ -497,6 +848,86 @@ func (c *Command) checkRequired() error {
 	return merr.ErrorOrNil()
 }
 
+// checkConstraints checks every constraint on the command and returns all of
+// the rules that were broken.
+func (c *Command) checkConstraints() error {
+	var merr multierror.Error
+
+	for _, constraint := range c.Constraints {
+		merr.Append(constraint.Check(c.Flags))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// checkConstraintFlags returns an error for every flag named in a constraint
+// that the command doesn't have, which is most likely a typo that would
+// otherwise make the constraint never apply.
+func (c *Command) checkConstraintFlags() error {
+	var merr multierror.Error
+
+	for _, constraint := range c.Constraints {
+		namer, ok := constraint.(flagNamer)
+		if !ok {
+			continue
+		}
+
+		for _, name := range namer.flagNames() {
+			if c.Flags.Lookup(name) == nil {
+				merr.Append(ErrConstraintFlagUndefined{
+					Name:       displayFlag(name),
+					Constraint: constraint.String(),
+				})
+			}
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// bindFiles points InputFile and OutputFile values at the command's input and
+// output.
+func (c *Command) bindFiles() {
+	for _, opt := range c.options() {
+		switch v := opt.Options().Value.(type) {
+		case *InputFile:
+			v.stdin = c.Input()
+		case *OutputFile:
+			v.stdout = c.Output()
+		}
+	}
+}
+
+// closeFiles closes every InputFile and OutputFile value. If the command didn't
+// succeed, anything written to an OutputFile is thrown away.
+func (c *Command) closeFiles(succeeded bool) error {
+	var merr multierror.Error
+
+	for _, opt := range c.options() {
+		switch v := opt.Options().Value.(type) {
+		case *InputFile:
+			merr.Append(v.Close())
+		case *OutputFile:
+			if succeeded {
+				merr.Append(v.Close())
+			} else {
+				merr.Append(v.Discard())
+			}
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// options returns the command's flags and arguments together.
+func (c *Command) options() []option {
+	options := make([]option, 0, len(c.Flags)+len(c.Args))
+	options = append(options, c.Flags...)
+	options = append(options, c.Args...)
+
+	return options
+}
+
 // errOrPrintHelp checks if the error returned is ErrPrintHelp. If so, then the
 // user intends to print help text to the command's output and not actually
 // return an error.
@@ -515,7 +946,13 @@ func (c *Command) getCommands() []*Command {
 		commands = append(commands, cmd)
 	}
 
-	sort.Sort(SortCommandsByName(commands))
+	if c.KeepOrder {
+		sort.Slice(commands, func(i, j int) bool {
+			return commands[i].order < commands[j].order
+		})
+	} else {
+		sort.Sort(SortCommandsByName(commands))
+	}
 
 	return commands
 }
@@ -529,137 +966,234 @@ func (c *Command) sortCommands() {
 	c.commands = m
 }
 
-// sortFlags sorts flags by name.
+// sortFlags sorts flags by name unless KeepOrder is set. The order groups of
+// local flags are declared in is kept either way.
 func (c *Command) sortFlags() {
-	sort.Sort(SortFlagsByName(c.Flags))
+	c.flagGroups = make([]string, 0)
+
+	for _, flag := range c.localFlags() {
+		c.flagGroups = appendGroup(c.flagGroups, flag.Options().Group)
+	}
+
+	if !c.KeepOrder {
+		sort.Sort(SortFlagsByName(c.Flags))
+	}
 }
 
-// generateUsage generates usage strings for commands, flags, and arguments.
-func (c *Command) generateUsage() {
-	builder := help.NewBuilder()
-	indent := 4
-	padding := 4
-	description := c.LongDesc
-	if description == "" {
-		description = formatDesc(c.Desc)
+// appendGroup appends group to groups if it isn't empty or already there.
+func appendGroup(groups []string, group string) []string {
+	if group == "" {
+		return groups
+	}
+
+	for _, g := range groups {
+		if g == group {
+			return groups
+		}
 	}
 
-	builder.Text(description)
-	builder.Newline()
-	builder.Newline()
-	builder.Header("USAGE:")
-	builder.Newline()
-	builder.Text(builder.WithIndent(c.FullName(), 4))
+	return append(groups, group)
+}
 
-	if len(c.Flags) > 0 {
-		builder.Text(" [flags]")
+// generateUsage renders the help for the command with the closest
+// HelpRenderer or HelpTemplate, or the default renderer if there isn't one.
+func (c *Command) generateUsage() error {
+	renderer, err := c.helpRenderer()
+	if err != nil {
+		return err
 	}
 
-	for _, arg := range c.Args {
-		opt := arg.Options()
-		if opt.IsSlice {
-			builder.Text(" <%s>...", opt.Name)
+	var sb strings.Builder
+	if err := renderer.Render(&sb, c.helpModel()); err != nil {
+		return err
+	}
+
+	c.usage = sb.String()
+
+	return nil
+}
+
+// helpRenderer returns the renderer set on the command or the closest parent.
+func (c *Command) helpRenderer() (help.Renderer, error) {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.HelpRenderer != nil {
+			return cmd.HelpRenderer, nil
+		}
+
+		if cmd.HelpTemplate != "" {
+			return help.NewTemplateRenderer(cmd.HelpTemplate, c.helpOptions()...)
+		}
+	}
+
+	return c.defaultRenderer(), nil
+}
+
+// defaultRenderer returns the renderer used when a command doesn't set one.
+func (c *Command) defaultRenderer() *help.DefaultRenderer {
+	return &help.DefaultRenderer{
+		Width:   termenv.Width(c.Output()),
+		Options: c.helpOptions(),
+	}
+}
+
+// helpOptions returns the options of the help builder for the command.
+func (c *Command) helpOptions() []help.Option {
+	return []help.Option{
+		help.WithWidth(termenv.Width(c.Output())),
+		help.WithColor(c.ColorEnabled()),
+		help.WithTheme(c.getTheme()),
+	}
+}
+
+// getTheme returns the theme set on the command or the closest parent.
+func (c *Command) getTheme() theme.Theme {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.Theme != nil {
+			return *cmd.Theme
+		}
+	}
+
+	return theme.Default()
+}
+
+// helpModel returns the structured help for the command.
+func (c *Command) helpModel() help.Command {
+	model := help.Command{
+		Name:       c.Name,
+		FullName:   c.FullName(),
+		Usage:      c.usageLine(),
+		Desc:       formatDesc(c.Desc),
+		LongDesc:   c.LongDesc,
+		FlagGroups: c.flagGroups,
+	}
+
+	declared := c.visibleCommands()
+	sort.Slice(declared, func(i, j int) bool {
+		return declared[i].order < declared[j].order
+	})
+
+	for _, cmd := range declared {
+		model.CommandGroups = appendGroup(model.CommandGroups, cmd.Group)
+	}
+
+	for _, cmd := range c.visibleCommands() {
+		model.Commands = append(model.Commands, help.Command{
+			Name:     cmd.Name,
+			FullName: cmd.FullName(),
+			Desc:     formatDesc(cmd.Desc),
+			Group:    cmd.Group,
+		})
+	}
+
+	for _, flag := range c.Flags {
+		if c.isInherited(flag) {
+			model.GlobalFlags = append(model.GlobalFlags, helpFlags(flag.Options())...)
 		} else {
-			builder.Text(" <%s>", opt.Name)
+			model.Flags = append(model.Flags, helpFlags(flag.Options())...)
 		}
 	}
 
-	if len(c.commands) > 0 {
-		builder.Text(" [command]")
+	for _, arg := range c.Args {
+		opt := arg.Options()
+
+		model.Args = append(model.Args, help.Arg{
+			Name:     opt.Name,
+			Desc:     formatDesc(opt.Desc),
+			Required: opt.Required,
+			Variadic: opt.IsSlice,
+		})
 	}
 
-	if len(c.commands) > 0 {
-		commands := tablewriter.NewWriter()
+	for _, constraint := range c.Constraints {
+		model.Constraints = append(model.Constraints, constraint.String())
+	}
 
-		builder.Newline()
-		builder.Newline()
-		builder.Header("COMMANDS:")
-		builder.Newline()
+	for _, example := range c.Examples {
+		model.Examples = append(model.Examples, help.Example{
+			Command: example.Command,
+			Desc:    example.Desc,
+		})
+	}
 
-		for _, cmd := range c.getCommands() {
-			commands.AddLine(
-				tablewriter.Cell{
-					Indent:  indent,
-					Padding: padding,
-					Text:    builder.Green(cmd.Name),
-				},
-				tablewriter.Cell{
-					Padding: padding,
-					Text:    formatDesc(cmd.Desc),
-				},
-			)
+	if c.parent == nil {
+		for _, topic := range c.HelpTopics {
+			model.Topics = append(model.Topics, helpTopic(topic))
 		}
+	}
+
+	return model
+}
+
+func helpTopic(topic HelpTopic) help.Topic {
+	return help.Topic{
+		Name: topic.Name,
+		Desc: formatDesc(topic.Desc),
+		Text: topic.Text,
+	}
+}
 
-		builder.Table(commands)
+// helpFlags returns the help for a flag. Secret flags also get a row for the
+// flag that reads them from a file.
+func helpFlags(opt Options) []help.Flag {
+	flags := []help.Flag{
+		{
+			Name:        opt.Name,
+			Shorthand:   opt.Shorthand,
+			Placeholder: placeholder(opt),
+			Desc:        formatDesc(opt.Desc),
+			Group:       opt.Group,
+			Default:     defaultText(opt),
+			EnvVar:      envVarName(opt),
+			Example:     opt.Example,
+			Required:    opt.Required,
+		},
 	}
 
+	if opt.Secret && opt.Name != "" {
+		flags = append(flags, help.Flag{
+			Name:  opt.Name + secretFileSuffix,
+			Desc:  fmt.Sprintf("Read --%s from a file (\"-\" for stdin)", opt.Name),
+			Group: opt.Group,
+		})
+	}
+
+	return flags
+}
+
+// usageLine returns the usage line of the command (e.g. "app get [flags]
+// <name>").
+func (c *Command) usageLine() string {
+	var sb strings.Builder
+
+	sb.WriteString(c.FullName())
+
 	if len(c.Flags) > 0 {
-		flags := tablewriter.NewWriter()
-
-		builder.Newline()
-		builder.Newline()
-		builder.Header("FLAGS:")
-		builder.Newline()
-
-		for _, flag := range c.Flags {
-			opt := flag.Options()
-
-			flags.AddLine(
-				tablewriter.Cell{
-					Indent: indent,
-					Text:   builder.Green("-%s", opt.Shorthand),
-					Suffix: ", ",
-				},
-				tablewriter.Cell{
-					Padding: padding,
-					Text:    builder.Green("--%s", opt.Name),
-				},
-				tablewriter.Cell{
-					Text: formatDesc(opt.Desc),
-				},
-			)
-		}
-
-		builder.Table(flags)
-	}
-
-	if len(c.Args) > 0 {
-		args := tablewriter.NewWriter()
-
-		builder.Newline()
-		builder.Newline()
-		builder.Header("ARGS:")
-		builder.Newline()
-
-		for _, arg := range c.Args {
-			opt := arg.Options()
-			text := builder.Green("<%s>", opt.Name)
-			if opt.IsSlice {
-				text += builder.Green("...")
-			}
+		sb.WriteString(" [flags]")
+	}
 
-			args.AddLine(
-				tablewriter.Cell{
-					Indent:  indent,
-					Padding: padding,
-					Text:    text,
-				},
-				tablewriter.Cell{
-					Text: formatDesc(opt.Desc),
-				},
-			)
+	for _, arg := range c.Args {
+		opt := arg.Options()
+		if opt.IsSlice {
+			fmt.Fprintf(&sb, " <%s>...", opt.Name)
+		} else {
+			fmt.Fprintf(&sb, " <%s>", opt.Name)
 		}
+	}
 
-		builder.Table(args)
+	if len(c.visibleCommands()) > 0 {
+		sb.WriteString(" [command]")
 	}
 
-	if len(c.commands) > 0 {
-		builder.Newline()
-		builder.Newline()
-		builder.Text("Use \"%s [command] --help\" for more information about a command.", c.FullName())
+	return sb.String()
+}
+
+// defaultText returns the default of an option as it's shown in help.
+func defaultText(opt Options) string {
+	if opt.DefaultDesc != "" {
+		return opt.DefaultDesc
 	}
 
-	c.usage = builder.String()
+	return opt.DefaultString
 }
 
 // SortCommandsByName sorts commands by name.
@@ -674,15 +1208,118 @@ func (n SortCommandsByName) Less(i, j int) bool {
 // Execute parses args and sets up the root command and it's children.
 func Execute(runner Runner, args []string) error {
 	p := parser.New(args)
+	cmd := newRoot(runner)
+	cmd.stmt = p.Parse()
+	cmd.setColor(args[1:])
+	cmd.setPager(args[1:])
+
+	return cmd.styleError(cmd.parseCommands(args[1:]))
+}
+
+// Prepare sets up the root command and it's children like Execute, but without
+// parsing any arguments or running anything. It's used to inspect the command
+// tree (e.g. to generate docs).
+func Prepare(runner Runner) (*Command, error) {
+	cmd := newRoot(runner)
+
+	err := cmd.Visit(func(c *Command) error {
+		return c.prepare()
+	}, VisitDescendants)
+
+	return cmd, err
+}
+
+// setColor decides whether output is colored from --color in args and the
+// output of the command. It's decided before anything is parsed since help is
+// rendered up front and errors can happen before --color is reached.
+func (c *Command) setColor(args []string) {
+	mode := termenv.ColorAuto
+
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "--" {
+			break
+		}
+
+		if !isFlag(args[i]) || !matchesFlag(args[i], newColorFlag()) {
+			continue
+		}
+
+		// An invalid mode is reported when the flag is parsed.
+		if m, err := termenv.ParseColorMode(args[i+1]); err == nil {
+			mode = m
+		}
+	}
+
+	color := termenv.ColorEnabled(c.Output(), mode)
+	c.color = &color
+}
+
+// ColorEnabled returns true if the output of the command is colored. Execute
+// decides from --color and the output of the root command; before that, it's
+// colored if the output is a terminal.
+func (c *Command) ColorEnabled() bool {
+	root := c.root()
+	if root.color != nil {
+		return *root.color
+	}
+
+	return termenv.ColorEnabled(root.Output(), termenv.ColorAuto)
+}
+
+// styleError returns err rendered with the theme of the command and its
+// colors, since the error is usually printed after Execute returns.
+func (c *Command) styleError(err error) error {
+	switch err.(type) {
+	case renderer, *multierror.Error:
+		return styledError{
+			err: err,
+			style: errorStyle{
+				theme: c.getTheme(),
+				color: c.ColorEnabled(),
+			},
+		}
+	}
+
+	return err
+}
+
+// newRoot initializes the root command of runner.
+func newRoot(runner Runner) *Command {
 	cmd := runner.Init()
 	cmd.setRunners(runner)
 	cmd.init()
-	cmd.stmt = p.Parse()
-	cmd.output = os.Stdout
 
 	if !cmd.HasFlag(HelpFlag.Name, HelpFlag.Shorthand) {
 		cmd.Flags = append(cmd.Flags, HelpFlag)
 	}
 
-	return cmd.parseCommands(args[1:])
+	if color := newColorFlag(); !cmd.HasFlag(color.Name, color.Shorthand) {
+		cmd.Flags = append(cmd.Flags, color)
+	}
+
+	if noPager := newNoPagerFlag(); cmd.Pager && !cmd.HasFlag(noPager.Name, noPager.Shorthand) {
+		cmd.Flags = append(cmd.Flags, noPager)
+	}
+
+	if _, ok := cmd.commands["help"]; !ok && (len(cmd.commands) > 0 || len(cmd.HelpTopics) > 0) {
+		cmd.AddCommands(&helpCommand{})
+	}
+
+	if _, ok := cmd.commands[specCommandName]; cmd.SpecCommand && !ok {
+		cmd.AddCommands(&specCommand{})
+	}
+
+	return cmd
+}
+
+// descendants returns the command and every command below it, parents before
+// children.
+func (c *Command) descendants() []*Command {
+	commands := []*Command{c}
+
+	for _, cmd := range c.getCommands() {
+		commands = append(commands, cmd.descendants()...)
+	}
+
+	return commands
 }
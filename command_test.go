@@ -1 +1,238 @@
 package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli/internal/errors"
+	"github.com/rdeusser/cli/theme"
+)
+
+func TestPersistentFlags(t *testing.T) {
+	testCases := []struct {
+		name            string
+		args            []string
+		wantDebug       bool
+		wantRootOutput  string
+		wantChildOutput string
+		wantErr         string
+	}{
+		{name: "Inherited", args: []string{"get", "--debug"}, wantDebug: true},
+		{name: "Local flags aren't inherited", args: []string{"get", "-A"}, wantErr: "-A"},
+		{name: "Shadowed", args: []string{"get", "-o", "json"}, wantChildOutput: "json"},
+		{name: "Not shadowed on the root", args: []string{"-o", "json"}, wantRootOutput: "json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var debug, all bool
+			var rootOutput, childOutput string
+
+			root := &Command{
+				Name: "test",
+				Flags: Flags{
+					&Flag[bool]{Name: "debug", Value: &debug, Persistent: true},
+					&Flag[string]{Name: "output", Shorthand: "o", Value: &rootOutput, Persistent: true},
+					&Flag[bool]{Shorthand: "A", Value: &all},
+				},
+			}
+			root.AddCommands(&testCommand{
+				cmd: &Command{
+					Name: "get",
+					Flags: Flags{
+						&Flag[string]{Name: "output", Shorthand: "o", Value: &childOutput},
+					},
+				},
+			})
+
+			_, err := execute(root, tc.args...)
+			if tc.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantDebug, debug)
+			assert.Equal(t, tc.wantRootOutput, rootOutput)
+			assert.Equal(t, tc.wantChildOutput, childOutput)
+		})
+	}
+
+	t.Run("Shorthand conflict", func(t *testing.T) {
+		root := &Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[string]{Name: "output", Shorthand: "o", Persistent: true},
+			},
+		}
+		root.AddCommands(&testCommand{
+			cmd: &Command{
+				Name: "get",
+				Flags: Flags{
+					&Flag[string]{Name: "owner", Shorthand: "o"},
+				},
+			},
+		})
+
+		_, err := execute(root, "get")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "already defined")
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		root := &Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[bool]{Name: "debug", Desc: "Enable debug logging", Persistent: true},
+				&Flag[string]{Name: "output", Shorthand: "o", Desc: "Output format", Persistent: true},
+				&Flag[bool]{Shorthand: "A", Desc: "All namespaces"},
+			},
+		}
+		root.AddCommands(&testCommand{
+			cmd: &Command{
+				Name: "get",
+				Flags: Flags{
+					&Flag[string]{Name: "output", Shorthand: "o", Desc: "Output format for get"},
+				},
+			},
+		})
+
+		out, err := execute(root, "get", "--help")
+		assert.NoError(t, err)
+
+		flags := strings.Index(out, "FLAGS:")
+		global := strings.Index(out, "GLOBAL FLAGS:")
+
+		assert.Greater(t, global, flags)
+		assert.Contains(t, out[flags:global], "Output format for get")
+		assert.Contains(t, out[global:], "--debug")
+		assert.NotContains(t, out, "All namespaces")
+	})
+}
+
+func TestColor(t *testing.T) {
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		args    []string
+		colored bool
+	}{
+		{name: "Not a terminal", args: []string{"--help"}},
+		{name: "Not a terminal error", args: []string{"--count", "abc"}},
+		{name: "Always", args: []string{"--color", "always", "--help"}, colored: true},
+		{name: "Always error", args: []string{"--count", "abc", "--color", "always"}, colored: true},
+		{name: "CLICOLOR_FORCE", env: map[string]string{"CLICOLOR_FORCE": "1"}, args: []string{"--help"}, colored: true},
+		{name: "CLICOLOR_FORCE and never", env: map[string]string{"CLICOLOR_FORCE": "1"}, args: []string{"--color", "never", "--help"}},
+		{name: "NO_COLOR", env: map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, args: []string{"--help"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("CLICOLOR_FORCE", "")
+
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			out, err := execute(&Command{
+				Name: "test",
+				Flags: Flags{
+					&Flag[int]{Name: "count", Desc: "How many"},
+				},
+			}, tc.args...)
+			if err != nil {
+				out = err.Error()
+			}
+
+			assert.Equal(t, tc.colored, strings.Contains(out, "\x1b["), out)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := execute(&Command{Name: "test"}, "--color", "sometimes")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown color mode "sometimes"`)
+	})
+
+	t.Run("Per root", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		t.Setenv("CLICOLOR_FORCE", "")
+
+		colored := &Command{Name: "test", Flags: Flags{&Flag[int]{Name: "count"}}}
+		plain := &Command{Name: "test", Flags: Flags{&Flag[int]{Name: "count"}}}
+
+		_, colorErr := execute(colored, "--count", "abc", "--color", "always")
+		_, plainErr := execute(plain, "--count", "abc")
+
+		// Each root keeps its own decision and flag, so executing one doesn't
+		// change the other or errors it already returned.
+		assert.True(t, colored.ColorEnabled())
+		assert.False(t, plain.ColorEnabled())
+		assert.Contains(t, colorErr.Error(), "\x1b[")
+		assert.NotContains(t, plainErr.Error(), "\x1b[")
+		assert.NotSame(t, colored.Flags.Lookup("color"), plain.Flags.Lookup("color"))
+		assert.True(t, errors.As(colorErr, &ErrInvalidValue{}))
+	})
+
+	t.Run("Reset between runs", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		t.Setenv("CLICOLOR_FORCE", "")
+
+		tc := &testCommand{cmd: &Command{Name: "test"}}
+
+		_, err := executeRunner(tc, "--color", "always")
+		assert.NoError(t, err)
+		assert.True(t, tc.cmd.ColorEnabled())
+
+		_, err = executeRunner(tc)
+		assert.NoError(t, err)
+		assert.False(t, tc.cmd.ColorEnabled())
+	})
+}
+
+func TestTheme(t *testing.T) {
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm")
+
+	custom := theme.Default()
+	custom.Header = theme.Style{Foreground: "5", Underline: true}
+	custom.Flag = theme.Style{Foreground: "6", Bold: true}
+	custom.Error = theme.Style{Foreground: "4"}
+
+	t.Run("Help", func(t *testing.T) {
+		out, err := execute(&Command{
+			Name:  "test",
+			Theme: &custom,
+			Flags: Flags{
+				&Flag[int]{Name: "count"},
+			},
+		}, "--color", "always", "--help")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "\x1b[35;4mUSAGE:\x1b[0m")
+		assert.Contains(t, out, "\x1b[36;1m--count\x1b[0m")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := execute(&Command{
+			Name:  "test",
+			Theme: &custom,
+			Flags: Flags{
+				&Flag[int]{Name: "count"},
+			},
+		}, "--color", "always", "--count", "abc")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "\x1b[34merror: \x1b[0m")
+	})
+
+	t.Run("Inherited", func(t *testing.T) {
+		root := &Command{Name: "root", Theme: &custom}
+		child := &Command{Name: "child", parent: root}
+
+		assert.Equal(t, custom, child.getTheme())
+		assert.Equal(t, theme.Default(), (&Command{Name: "other"}).getTheme())
+	})
+}
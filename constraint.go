@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a rule across flags that's checked after every flag has been
+// set from the command line, environment variables, and defaults.
+type Constraint interface {
+	// Check returns an error if the rule is broken.
+	Check(flags Flags) error
+
+	// String describes the rule. It's shown in the CONSTRAINTS section of
+	// help.
+	String() string
+}
+
+// flagNamer is implemented by the constraints in this package so the flags
+// they name can be checked against the flags of the command.
+type flagNamer interface {
+	flagNames() []string
+}
+
+var (
+	_ Constraint = mutuallyExclusive{}
+	_ Constraint = requiredTogether{}
+	_ Constraint = atLeastOne{}
+	_ Constraint = requiredIf{}
+)
+
+// MutuallyExclusive returns a constraint where at most one of the named flags
+// can be set.
+func MutuallyExclusive(names ...string) Constraint {
+	return mutuallyExclusive{names: names}
+}
+
+type mutuallyExclusive struct {
+	names []string
+}
+
+func (c mutuallyExclusive) Check(flags Flags) error {
+	set := setFlags(flags, c.names)
+	if len(set) > 1 {
+		return ErrFlagsMutuallyExclusive{Names: set}
+	}
+
+	return nil
+}
+
+func (c mutuallyExclusive) String() string {
+	return fmt.Sprintf("%s can't be used together", joinFlagNames(c.names, "and"))
+}
+
+func (c mutuallyExclusive) flagNames() []string {
+	return c.names
+}
+
+// RequiredTogether returns a constraint where either all or none of the named
+// flags must be set.
+func RequiredTogether(names ...string) Constraint {
+	return requiredTogether{names: names}
+}
+
+type requiredTogether struct {
+	names []string
+}
+
+func (c requiredTogether) Check(flags Flags) error {
+	set := setFlags(flags, c.names)
+	if len(set) == 0 || len(set) == len(c.names) {
+		return nil
+	}
+
+	missing := make([]string, 0)
+	for _, name := range c.names {
+		if !isFlagSet(flags, name) {
+			missing = append(missing, displayFlag(name))
+		}
+	}
+
+	return ErrFlagsRequiredTogether{
+		Set:     set,
+		Missing: missing,
+	}
+}
+
+func (c requiredTogether) String() string {
+	return fmt.Sprintf("%s must be used together", joinFlagNames(c.names, "and"))
+}
+
+func (c requiredTogether) flagNames() []string {
+	return c.names
+}
+
+// AtLeastOne returns a constraint where at least one of the named flags must be
+// set.
+func AtLeastOne(names ...string) Constraint {
+	return atLeastOne{names: names}
+}
+
+type atLeastOne struct {
+	names []string
+}
+
+func (c atLeastOne) Check(flags Flags) error {
+	if len(setFlags(flags, c.names)) == 0 {
+		return ErrFlagsAtLeastOne{Names: displayFlags(c.names)}
+	}
+
+	return nil
+}
+
+func (c atLeastOne) String() string {
+	return fmt.Sprintf("at least one of %s is required", joinFlagNames(c.names, "or"))
+}
+
+func (c atLeastOne) flagNames() []string {
+	return c.names
+}
+
+// RequiredIf returns a constraint where the flag name must be set when the flag
+// other has been set to value.
+func RequiredIf(name, other, value string) Constraint {
+	return requiredIf{
+		name:  name,
+		other: other,
+		value: value,
+	}
+}
+
+type requiredIf struct {
+	name  string
+	other string
+	value string
+}
+
+func (c requiredIf) Check(flags Flags) error {
+	flag := flags.Lookup(c.other)
+	// The raw value is compared since String redacts secret flags.
+	if flag == nil || rawString(flag) != c.value {
+		return nil
+	}
+
+	if !isFlagSet(flags, c.name) {
+		return ErrFlagRequiredIf{
+			Name:  displayFlag(c.name),
+			Other: displayFlag(c.other),
+			Value: c.value,
+		}
+	}
+
+	return nil
+}
+
+func (c requiredIf) String() string {
+	return fmt.Sprintf("%s is required when %s is %q", displayFlag(c.name), displayFlag(c.other), c.value)
+}
+
+func (c requiredIf) flagNames() []string {
+	return []string{c.name, c.other}
+}
+
+// isFlagSet returns true if the flag has been set from the command line or an
+// environment variable.
+func isFlagSet(flags Flags, name string) bool {
+	flag := flags.Lookup(name)
+	if flag == nil {
+		return false
+	}
+
+	return flag.Options().HasBeenSet
+}
+
+// setFlags returns the display names of each flag in names that has been set.
+func setFlags(flags Flags, names []string) []string {
+	set := make([]string, 0)
+
+	for _, name := range names {
+		if isFlagSet(flags, name) {
+			set = append(set, displayFlag(name))
+		}
+	}
+
+	return set
+}
+
+// displayFlag returns name the way it would be typed on the command line.
+func displayFlag(name string) string {
+	name = trimDash(name)
+	if len(name) == 1 {
+		return fmt.Sprintf("-%s", name)
+	}
+
+	return fmt.Sprintf("--%s", name)
+}
+
+func displayFlags(names []string) []string {
+	display := make([]string, 0, len(names))
+	for _, name := range names {
+		display = append(display, displayFlag(name))
+	}
+
+	return display
+}
+
+// joinFlagNames joins names into a list like "--a, --b and --c".
+func joinFlagNames(names []string, conjunction string) string {
+	return joinList(displayFlags(names), conjunction)
+}
+
+func joinList(items []string, conjunction string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+
+	return fmt.Sprintf("%s %s %s", strings.Join(items[:len(items)-1], ", "), conjunction, items[len(items)-1])
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraints(t *testing.T) {
+	testCases := []struct {
+		name        string
+		flags       Flags
+		constraints []Constraint
+		args        []string
+		wantErr     []string
+	}{
+		{
+			name:        "Mutually exclusive",
+			flags:       Flags{&Flag[string]{Name: "file"}, &Flag[bool]{Name: "stdin"}},
+			constraints: []Constraint{MutuallyExclusive("file", "stdin")},
+			args:        []string{"--file", "a.yaml", "--stdin"},
+			wantErr:     []string{"--file and --stdin can't be used together"},
+		},
+		{
+			name:        "Mutually exclusive with one set",
+			flags:       Flags{&Flag[string]{Name: "file"}, &Flag[bool]{Name: "stdin"}},
+			constraints: []Constraint{MutuallyExclusive("file", "stdin")},
+			args:        []string{"--stdin"},
+		},
+		{
+			name:        "Required together",
+			flags:       Flags{&Flag[string]{Name: "cert"}, &Flag[string]{Name: "key"}},
+			constraints: []Constraint{RequiredTogether("cert", "key")},
+			args:        []string{"--cert", "a"},
+			wantErr:     []string{"--cert must be used with --key"},
+		},
+		{
+			name:        "Required together with both set",
+			flags:       Flags{&Flag[string]{Name: "cert"}, &Flag[string]{Name: "key"}},
+			constraints: []Constraint{RequiredTogether("cert", "key")},
+			args:        []string{"--cert", "a", "--key", "b"},
+		},
+		{
+			name:        "At least one",
+			flags:       Flags{&Flag[string]{Name: "file"}, &Flag[bool]{Name: "stdin"}},
+			constraints: []Constraint{AtLeastOne("file", "stdin")},
+			wantErr:     []string{"at least one of --file or --stdin is required"},
+		},
+		{
+			name:        "Required if",
+			flags:       Flags{&Flag[string]{Name: "cloud", Default: "gcp"}, &Flag[string]{Name: "region"}},
+			constraints: []Constraint{RequiredIf("region", "cloud", "aws")},
+			args:        []string{"--cloud", "aws"},
+			wantErr:     []string{`--region is required when --cloud is "aws"`},
+		},
+		{
+			name:        "Required if with another value",
+			flags:       Flags{&Flag[string]{Name: "cloud", Default: "gcp"}, &Flag[string]{Name: "region"}},
+			constraints: []Constraint{RequiredIf("region", "cloud", "aws")},
+		},
+		{
+			name:        "Required if secret",
+			flags:       Flags{&Flag[string]{Name: "password", Secret: true}, &Flag[bool]{Name: "insecure"}},
+			constraints: []Constraint{RequiredIf("insecure", "password", "hunter2")},
+			args:        []string{"--password", "hunter2"},
+			wantErr:     []string{"--insecure is required"},
+		},
+		{
+			name:        "Required if secret with another value",
+			flags:       Flags{&Flag[string]{Name: "password", Secret: true}, &Flag[bool]{Name: "insecure"}},
+			constraints: []Constraint{RequiredIf("insecure", "password", "hunter2")},
+			args:        []string{"--password", "s3cret"},
+		},
+		{
+			name: "Many violations",
+			flags: Flags{
+				&Flag[string]{Name: "file"},
+				&Flag[bool]{Name: "stdin"},
+				&Flag[string]{Name: "cert"},
+				&Flag[string]{Name: "key"},
+				&Flag[string]{Name: "cloud"},
+				&Flag[string]{Name: "region"},
+			},
+			constraints: []Constraint{
+				RequiredTogether("cert", "key"),
+				AtLeastOne("file", "stdin"),
+				RequiredIf("region", "cloud", "aws"),
+			},
+			args:    []string{"--cert", "a", "--cloud", "aws"},
+			wantErr: []string{"--cert must be used with --key", "at least one of --file or --stdin is required", "--region is required"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := execute(&Command{
+				Name:        "test",
+				Flags:       tc.flags,
+				Constraints: tc.constraints,
+			}, tc.args...)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			for _, want := range tc.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+
+	t.Run("Help", func(t *testing.T) {
+		out, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[string]{Name: "file"},
+				&Flag[bool]{Name: "stdin"},
+				&Flag[string]{Name: "cert"},
+				&Flag[string]{Name: "key"},
+			},
+			Constraints: []Constraint{
+				RequiredTogether("cert", "key"),
+				AtLeastOne("file", "stdin"),
+			},
+		}, "--help")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "CONSTRAINTS:")
+		assert.Contains(t, out, "--cert and --key must be used together")
+		assert.Contains(t, out, "at least one of --file or --stdin is required")
+	})
+
+	t.Run("Undefined flag", func(t *testing.T) {
+		cmd := &Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[string]{Name: "region"},
+				&Flag[string]{Name: "cloud"},
+			},
+			Constraints: []Constraint{
+				RequiredIf("regoin", "cloud", "aws"),
+			},
+		}
+
+		_, err := execute(cmd, "--cloud", "gcp")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `--regoin isn't defined, but is used in the constraint "--regoin is required when --cloud is \"aws\""`)
+	})
+
+	t.Run("Inherited flag", func(t *testing.T) {
+		root := &Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[bool]{Name: "verbose", Persistent: true},
+				&Flag[bool]{Name: "quiet", Persistent: true},
+			},
+		}
+		root.AddCommands(&testCommand{
+			cmd: &Command{
+				Name:        "get",
+				Constraints: []Constraint{MutuallyExclusive("verbose", "quiet")},
+			},
+		})
+
+		_, err := execute(root, "get", "--verbose", "--quiet")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "--verbose and --quiet can't be used together")
+	})
+}
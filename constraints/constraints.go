@@ -1,6 +1,7 @@
 package constraints
 
 import (
+	"math/big"
 	"net"
 	"net/url"
 	"time"
@@ -55,3 +56,13 @@ type URL interface {
 type IP interface {
 	net.IP | ~[]net.IP
 }
+
+// Big is a constraint for arbitrary-precision number types.
+type Big interface {
+	*big.Int | *big.Float | ~[]*big.Int | ~[]*big.Float
+}
+
+// Number is a constraint for scalar numeric types that can be ordered.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr | ~float32 | ~float64
+}
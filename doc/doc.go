@@ -0,0 +1,89 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rdeusser/cli"
+	"github.com/rdeusser/cli/help"
+)
+
+// genTree calls gen for cmd and every command below it, writing each page to
+// a file in dir named after the command with ext.
+func genTree(cmd *cli.Command, dir, ext string, gen func(*cli.Command, io.Writer) error) error {
+	if err := genFile(filepath.Join(dir, pageName(cmd.FullName())+ext), func(w io.Writer) error {
+		return gen(cmd, w)
+	}); err != nil {
+		return err
+	}
+
+	for _, c := range cmd.Commands() {
+		if err := genTree(c, dir, ext, gen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// genFile creates path and writes to it with gen.
+func genFile(path string, gen func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := gen(f); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// helpModel returns the help model of cmd with only the subcommands that get
+// pages, so every command in it can be linked to.
+func helpModel(cmd *cli.Command) help.Command {
+	model := cmd.HelpModel()
+
+	pages := make(map[string]bool)
+	for _, c := range cmd.Commands() {
+		pages[c.Name] = true
+	}
+
+	commands := make([]help.Command, 0, len(model.Commands))
+	for _, c := range model.Commands {
+		if pages[c.Name] {
+			commands = append(commands, c)
+		}
+	}
+
+	model.Commands = commands
+
+	return model
+}
+
+// commands returns cmd and every command below it, parents before children.
+func commands(cmd *cli.Command) []*cli.Command {
+	result := []*cli.Command{cmd}
+
+	for _, c := range cmd.Commands() {
+		result = append(result, commands(c)...)
+	}
+
+	return result
+}
+
+// pageName returns the name of the page for a command without an extension
+// (e.g. "myapp-server-start").
+func pageName(fullName string) string {
+	return strings.Join(strings.Fields(fullName), "-")
+}
+
+// pageLink returns the file name of the page for a command.
+func pageLink(fullName, ext string) string {
+	return fmt.Sprintf("%s%s", pageName(fullName), ext)
+}
@@ -0,0 +1,143 @@
+package doc
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type testCommand struct {
+	cmd *cli.Command
+}
+
+func (tc *testCommand) Init() *cli.Command {
+	return tc.cmd
+}
+
+func (tc *testCommand) Run() error {
+	return nil
+}
+
+func newTestCommand(t *testing.T) *cli.Command {
+	t.Helper()
+
+	root := &cli.Command{
+		Name: "myapp",
+		Desc: "manage things",
+		LongDesc: `
+			Myapp manages things. Use the -v flag for more output.
+
+			Things can be:
+			- started with ` + "`myapp server start`" + `
+			- stopped
+
+			    myapp server start --port 8080
+		`,
+		Flags: cli.Flags{
+			&cli.Flag[bool]{Name: "verbose", Shorthand: "v", Desc: "verbose output", Persistent: true},
+			&cli.Flag[string]{Name: "config", Desc: "path to the config file", Default: "/etc/myapp.yaml", EnvVar: cli.EnvVar[string]{Name: "MYAPP_CONFIG"}},
+		},
+	}
+
+	server := &cli.Command{
+		Name: "server",
+		Desc: "manage the server",
+	}
+
+	server.AddCommands(&testCommand{
+		cmd: &cli.Command{
+			Name: "start",
+			Desc: "start the server",
+			Flags: cli.Flags{
+				&cli.Flag[int]{Name: "port", Shorthand: "p", Desc: "port to listen on", Default: 8080, Required: true, Group: "Networking"},
+			},
+			Args: cli.Args{
+				&cli.Arg[string]{Name: "name", Desc: "name of the server"},
+			},
+			Examples: []cli.Example{
+				{Command: "myapp server start -p 9000 web", Desc: "Start a server on port 9000"},
+			},
+		},
+	})
+
+	root.AddCommands(&testCommand{cmd: server})
+
+	cmd, err := cli.Prepare(&testCommand{cmd: root})
+	assert.NoError(t, err)
+
+	return cmd
+}
+
+// assertGolden compares the file at path with the golden file for the test.
+// Run the tests with -update to rewrite golden files.
+func assertGolden(t *testing.T, path string) {
+	t.Helper()
+
+	got, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	golden := filepath.Join("testdata", t.Name()+".golden")
+	if *update {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+		assert.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestGenMan(t *testing.T) {
+	root := newTestCommand(t)
+	dir := t.TempDir()
+
+	assert.NoError(t, GenManTree(root, ManHeader{Source: "myapp 1.0.0", Manual: "Myapp Manual"}, dir))
+
+	for _, name := range []string{"myapp.1", "myapp-server.1", "myapp-server-start.1"} {
+		t.Run(name, func(t *testing.T) {
+			assertGolden(t, filepath.Join(dir, name))
+		})
+	}
+
+	t.Run("Deterministic", func(t *testing.T) {
+		var a, b bytes.Buffer
+
+		assert.NoError(t, GenMan(root, ManHeader{}, &a))
+		assert.NoError(t, GenMan(newTestCommand(t), ManHeader{}, &b))
+		assert.Equal(t, a.String(), b.String())
+	})
+}
+
+func TestGenMarkdown(t *testing.T) {
+	root := newTestCommand(t)
+	dir := t.TempDir()
+
+	assert.NoError(t, GenMarkdownTree(root, dir))
+
+	for _, name := range []string{"index.md", "myapp.md", "myapp-server.md", "myapp-server-start.md"} {
+		t.Run(name, func(t *testing.T) {
+			assertGolden(t, filepath.Join(dir, name))
+		})
+	}
+}
+
+func TestGenHTML(t *testing.T) {
+	root := newTestCommand(t)
+	dir := t.TempDir()
+
+	assert.NoError(t, GenHTMLTree(root, dir))
+
+	for _, name := range []string{"index.html", "myapp.html", "myapp-server-start.html"} {
+		t.Run(name, func(t *testing.T) {
+			assertGolden(t, filepath.Join(dir, name))
+		})
+	}
+}
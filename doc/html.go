@@ -0,0 +1,262 @@
+package doc
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rdeusser/cli"
+	"github.com/rdeusser/cli/help"
+)
+
+// GenHTMLTree writes an HTML page for cmd and every command below it to dir,
+// along with index.html that links to every page. Pages are named like man
+// pages (e.g. "myapp-server-start.html").
+func GenHTMLTree(cmd *cli.Command, dir string) error {
+	if err := genTree(cmd, dir, ".html", GenHTML); err != nil {
+		return err
+	}
+
+	return genFile(filepath.Join(dir, "index.html"), func(w io.Writer) error {
+		return GenHTMLIndex(cmd, w)
+	})
+}
+
+// GenHTMLIndex writes an HTML page to w that lists cmd and every command below
+// it with links to their pages.
+func GenHTMLIndex(cmd *cli.Command, w io.Writer) error {
+	var sb strings.Builder
+
+	writeHTMLHeader(&sb, cmd.FullName())
+	sb.WriteString("<ul>\n")
+
+	for _, c := range commands(cmd) {
+		model := helpModel(c)
+
+		fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a>", html.EscapeString(pageLink(model.FullName, ".html")), html.EscapeString(model.FullName))
+
+		if model.Desc != "" {
+			fmt.Fprintf(&sb, ": %s", htmlText(model.Desc))
+		}
+
+		sb.WriteString("</li>\n")
+	}
+
+	sb.WriteString("</ul>\n")
+	writeHTMLFooter(&sb)
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// GenHTML writes the HTML page for cmd to w. It has the same sections as the
+// Markdown page.
+func GenHTML(cmd *cli.Command, w io.Writer) error {
+	model := helpModel(cmd)
+
+	var sb strings.Builder
+
+	writeHTMLHeader(&sb, model.FullName)
+
+	if model.Desc != "" {
+		fmt.Fprintf(&sb, "<p>%s</p>\n", htmlText(model.Desc))
+	}
+
+	fmt.Fprintf(&sb, "<h2>Usage</h2>\n<pre><code>%s</code></pre>\n", html.EscapeString(model.Usage))
+
+	if model.LongDesc != "" {
+		sb.WriteString("<h2>Description</h2>\n")
+		writeHTMLText(&sb, model.LongDesc)
+	}
+
+	for _, group := range model.CommandGroups {
+		fmt.Fprintf(&sb, "<h2>%s</h2>\n", html.EscapeString(group))
+		writeHTMLCommands(&sb, help.CommandsInGroup(model.Commands, group))
+	}
+
+	if cmds := help.CommandsInGroup(model.Commands, ""); len(cmds) > 0 {
+		sb.WriteString("<h2>Commands</h2>\n")
+		writeHTMLCommands(&sb, cmds)
+	}
+
+	for _, group := range model.FlagGroups {
+		fmt.Fprintf(&sb, "<h2>%s</h2>\n", html.EscapeString(group))
+		writeHTMLFlags(&sb, help.FlagsInGroup(model.Flags, group))
+	}
+
+	if flags := help.FlagsInGroup(model.Flags, ""); len(flags) > 0 {
+		sb.WriteString("<h2>Flags</h2>\n")
+		writeHTMLFlags(&sb, flags)
+	}
+
+	if len(model.GlobalFlags) > 0 {
+		sb.WriteString("<h2>Global flags</h2>\n")
+		writeHTMLFlags(&sb, model.GlobalFlags)
+	}
+
+	if len(model.Args) > 0 {
+		sb.WriteString("<h2>Arguments</h2>\n<table>\n")
+		sb.WriteString("<tr><th>Argument</th><th>Description</th></tr>\n")
+
+		for _, arg := range model.Args {
+			name := fmt.Sprintf("<%s>", arg.Name)
+			if arg.Variadic {
+				name += "..."
+			}
+
+			fmt.Fprintf(&sb, "<tr><td><code>%s</code></td><td>%s</td></tr>\n", html.EscapeString(name), htmlText(arg.Desc))
+		}
+
+		sb.WriteString("</table>\n")
+	}
+
+	if len(model.Constraints) > 0 {
+		sb.WriteString("<h2>Constraints</h2>\n<ul>\n")
+
+		for _, constraint := range model.Constraints {
+			fmt.Fprintf(&sb, "<li>%s</li>\n", html.EscapeString(constraint))
+		}
+
+		sb.WriteString("</ul>\n")
+	}
+
+	if len(model.Examples) > 0 {
+		sb.WriteString("<h2>Examples</h2>\n")
+
+		for _, example := range model.Examples {
+			if example.Desc != "" {
+				fmt.Fprintf(&sb, "<p>%s</p>\n", htmlText(example.Desc))
+			}
+
+			fmt.Fprintf(&sb, "<pre><code>%s</code></pre>\n", html.EscapeString(example.Command))
+		}
+	}
+
+	if links := seeAlsoLinks(cmd, ".html"); len(links) > 0 {
+		sb.WriteString("<h2>See also</h2>\n<ul>\n")
+
+		for _, link := range links {
+			fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a>", html.EscapeString(link.href), html.EscapeString(link.name))
+
+			if link.desc != "" {
+				fmt.Fprintf(&sb, ": %s", htmlText(link.desc))
+			}
+
+			sb.WriteString("</li>\n")
+		}
+
+		sb.WriteString("</ul>\n")
+	}
+
+	writeHTMLFooter(&sb)
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func writeHTMLHeader(sb *strings.Builder, title string) {
+	title = html.EscapeString(title)
+
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(sb, "<title>%s</title>\n</head>\n<body>\n<h1>%s</h1>\n", title, title)
+}
+
+func writeHTMLFooter(sb *strings.Builder) {
+	sb.WriteString("</body>\n</html>\n")
+}
+
+func writeHTMLCommands(sb *strings.Builder, commands []help.Command) {
+	sb.WriteString("<table>\n<tr><th>Command</th><th>Description</th></tr>\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(sb, "<tr><td><a href=\"%s\">%s</a></td><td>%s</td></tr>\n",
+			html.EscapeString(pageLink(c.FullName, ".html")),
+			html.EscapeString(c.Name),
+			htmlText(c.Desc),
+		)
+	}
+
+	sb.WriteString("</table>\n")
+}
+
+func writeHTMLFlags(sb *strings.Builder, flags []help.Flag) {
+	sb.WriteString("<table>\n<tr><th>Flag</th><th>Description</th><th>Default</th><th>Environment</th></tr>\n")
+
+	for _, flag := range flags {
+		desc := htmlText(flag.Desc)
+		if flag.Required {
+			desc = strings.TrimSpace(desc + " (required)")
+		}
+
+		if flag.Example != "" {
+			desc += fmt.Sprintf(" (e.g. <code>%s</code>)", html.EscapeString(flag.Example))
+		}
+
+		fmt.Fprintf(sb, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			htmlCode(flagUsage(flag)),
+			desc,
+			htmlCode(flag.Default),
+			htmlCode(flag.EnvVar),
+		)
+	}
+
+	sb.WriteString("</table>\n")
+}
+
+// writeHTMLText writes a description with markup (see help.ParseText) as
+// HTML.
+func writeHTMLText(sb *strings.Builder, s string) {
+	for _, block := range help.ParseText(s) {
+		switch block.Kind {
+		case help.BlockParagraph:
+			fmt.Fprintf(sb, "<p>%s</p>\n", htmlText(block.Lines[0]))
+		case help.BlockList:
+			sb.WriteString("<ul>\n")
+
+			for _, item := range block.Lines {
+				fmt.Fprintf(sb, "<li>%s</li>\n", htmlText(item))
+			}
+
+			sb.WriteString("</ul>\n")
+		case help.BlockCode:
+			fmt.Fprintf(sb, "<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(block.Lines, "\n")))
+		}
+	}
+}
+
+// htmlText escapes s and renders code spans as code.
+func htmlText(s string) string {
+	parts := strings.Split(s, "`")
+
+	var sb strings.Builder
+
+	for i, part := range parts {
+		// Odd parts are between backticks. An unmatched backtick is kept as
+		// text.
+		if i%2 == 1 && i < len(parts)-1 {
+			sb.WriteString(htmlCode(part))
+			continue
+		}
+
+		if i%2 == 1 {
+			sb.WriteString("`")
+		}
+
+		sb.WriteString(html.EscapeString(part))
+	}
+
+	return sb.String()
+}
+
+// htmlCode returns s escaped in a code element, or "" if s is empty.
+func htmlCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "<code>" + html.EscapeString(s) + "</code>"
+}
@@ -0,0 +1,287 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rdeusser/cli"
+	"github.com/rdeusser/cli/help"
+)
+
+// ManHeader is the header of a man page.
+type ManHeader struct {
+	// Section is the manual section (e.g. "1" for user commands). It
+	// defaults to "1".
+	Section string
+
+	// Date is shown in the footer. It's left out if it's zero so generated
+	// pages don't change every time they're generated.
+	Date time.Time
+
+	// Source is the source of the command, usually the name and version of
+	// the project (e.g. "myapp 1.2.3").
+	Source string
+
+	// Manual is the title of the manual (e.g. "User Commands").
+	Manual string
+}
+
+func (h ManHeader) section() string {
+	if h.Section == "" {
+		return "1"
+	}
+
+	return h.Section
+}
+
+// GenManTree writes a man page for cmd and every command below it to dir. Pages
+// are named after the full name of the command with spaces replaced by dashes
+// (e.g. "myapp-server-start.1"), which is what `man myapp-server-start` looks
+// for.
+func GenManTree(cmd *cli.Command, header ManHeader, dir string) error {
+	return genTree(cmd, dir, "."+header.section(), func(c *cli.Command, w io.Writer) error {
+		return GenMan(c, header, w)
+	})
+}
+
+// GenMan writes the man page for cmd to w. The output only depends on cmd and
+// header, so pages can be checked in and compared in tests.
+func GenMan(cmd *cli.Command, header ManHeader, w io.Writer) error {
+	model := helpModel(cmd)
+	section := header.section()
+
+	var sb strings.Builder
+
+	date := ""
+	if !header.Date.IsZero() {
+		date = header.Date.Format("January 2006")
+	}
+
+	fmt.Fprintf(&sb, ".TH %s %s %s %s %s\n",
+		quote(strings.ToUpper(pageName(model.FullName))),
+		quote(section),
+		quote(date),
+		quote(header.Source),
+		quote(header.Manual),
+	)
+
+	sb.WriteString(".SH NAME\n")
+	if model.Desc != "" {
+		fmt.Fprintf(&sb, "%s \\- %s\n", escape(pageName(model.FullName)), escape(model.Desc))
+	} else {
+		fmt.Fprintf(&sb, "%s\n", escape(pageName(model.FullName)))
+	}
+
+	sb.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&sb, "\\fB%s\\fP\n", escape(model.Usage))
+
+	if desc := model.Description(); desc != "" {
+		sb.WriteString(".SH DESCRIPTION\n")
+		writeText(&sb, desc)
+	}
+
+	if len(model.Commands) > 0 {
+		sb.WriteString(".SH COMMANDS\n")
+
+		for _, c := range model.Commands {
+			fmt.Fprintf(&sb, ".TP\n\\fB%s\\fP\n%s\n", escape(c.Name), escape(c.Desc))
+		}
+	}
+
+	if len(model.Flags) > 0 {
+		sb.WriteString(".SH OPTIONS\n")
+		writeFlags(&sb, model.Flags)
+	}
+
+	if len(model.GlobalFlags) > 0 {
+		sb.WriteString(".SH GLOBAL OPTIONS\n")
+		writeFlags(&sb, model.GlobalFlags)
+	}
+
+	if len(model.Args) > 0 {
+		sb.WriteString(".SH ARGUMENTS\n")
+
+		for _, arg := range model.Args {
+			name := fmt.Sprintf("<%s>", arg.Name)
+			if arg.Variadic {
+				name += "..."
+			}
+
+			fmt.Fprintf(&sb, ".TP\n\\fI%s\\fP\n%s\n", escape(name), escape(arg.Desc))
+		}
+	}
+
+	if len(model.Constraints) > 0 {
+		sb.WriteString(".SH CONSTRAINTS\n")
+
+		for _, constraint := range model.Constraints {
+			fmt.Fprintf(&sb, ".IP \\(bu 2\n%s\n", escape(constraint))
+		}
+	}
+
+	if len(model.Examples) > 0 {
+		sb.WriteString(".SH EXAMPLES\n")
+
+		for i, example := range model.Examples {
+			if i > 0 {
+				sb.WriteString(".PP\n")
+			}
+
+			if example.Desc != "" {
+				fmt.Fprintf(&sb, "%s\n.PP\n", escape(example.Desc))
+			}
+
+			fmt.Fprintf(&sb, ".RS 4\n.nf\n%s\n.fi\n.RE\n", escape(example.Command))
+		}
+	}
+
+	if seeAlso := seeAlso(cmd, section); len(seeAlso) > 0 {
+		sb.WriteString(".SH SEE ALSO\n")
+		sb.WriteString(strings.Join(seeAlso, ", "))
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// writeFlags writes flags as a tagged list with the default, environment
+// variable, and whether the flag is required under each description.
+func writeFlags(sb *strings.Builder, flags []help.Flag) {
+	for _, flag := range flags {
+		names := make([]string, 0, 2)
+		if flag.Shorthand != "" {
+			names = append(names, fmt.Sprintf("\\fB\\-%s\\fP", escape(flag.Shorthand)))
+		}
+
+		if flag.Name != "" {
+			names = append(names, fmt.Sprintf("\\fB\\-\\-%s\\fP", escape(flag.Name)))
+		}
+
+		tag := strings.Join(names, ", ")
+		if flag.Placeholder != "" {
+			tag += fmt.Sprintf(" \\fI%s\\fP", escape(flag.Placeholder))
+		}
+
+		fmt.Fprintf(sb, ".TP\n%s\n", tag)
+
+		if flag.Desc != "" {
+			fmt.Fprintf(sb, "%s\n", inline(flag.Desc))
+		}
+
+		if flag.Default != "" {
+			fmt.Fprintf(sb, ".br\nDefault: %s\n", escape(flag.Default))
+		}
+
+		if flag.EnvVar != "" {
+			fmt.Fprintf(sb, ".br\nEnvironment: \\fB%s\\fP\n", escape(flag.EnvVar))
+		}
+
+		if flag.Example != "" {
+			fmt.Fprintf(sb, ".br\nExample: %s\n", escape(flag.Example))
+		}
+
+		if flag.Required {
+			sb.WriteString(".br\nRequired.\n")
+		}
+	}
+}
+
+// writeText writes a description with markup (see help.ParseText) as roff.
+func writeText(sb *strings.Builder, s string) {
+	for i, block := range help.ParseText(s) {
+		switch block.Kind {
+		case help.BlockParagraph:
+			if i > 0 {
+				sb.WriteString(".PP\n")
+			}
+
+			fmt.Fprintf(sb, "%s\n", inline(block.Lines[0]))
+		case help.BlockList:
+			for _, item := range block.Lines {
+				fmt.Fprintf(sb, ".IP \\(bu 2\n%s\n", inline(item))
+			}
+		case help.BlockCode:
+			sb.WriteString(".PP\n.RS 4\n.nf\n")
+
+			for _, line := range block.Lines {
+				fmt.Fprintf(sb, "%s\n", escape(line))
+			}
+
+			sb.WriteString(".fi\n.RE\n")
+		}
+	}
+}
+
+// seeAlso returns references to the parent and subcommands of cmd.
+func seeAlso(cmd *cli.Command, section string) []string {
+	refs := make([]string, 0)
+
+	if parent := cmd.Parent(); parent != nil {
+		refs = append(refs, manRef(parent.FullName(), section))
+	}
+
+	for _, c := range cmd.Commands() {
+		refs = append(refs, manRef(c.FullName(), section))
+	}
+
+	return refs
+}
+
+func manRef(fullName, section string) string {
+	return fmt.Sprintf("\\fB%s\\fP(%s)", escape(pageName(fullName)), section)
+}
+
+// inline escapes s and renders code spans in bold.
+func inline(s string) string {
+	parts := strings.Split(s, "`")
+
+	var sb strings.Builder
+
+	for i, part := range parts {
+		// Odd parts are between backticks. An unmatched backtick is kept as
+		// text.
+		if i%2 == 1 && i < len(parts)-1 {
+			fmt.Fprintf(&sb, "\\fB%s\\fP", escapeText(part))
+			continue
+		}
+
+		if i%2 == 1 {
+			sb.WriteString("`")
+		}
+
+		sb.WriteString(escapeText(part))
+	}
+
+	return guardLine(sb.String())
+}
+
+// escape escapes s so it's shown as-is on a line of its own.
+func escape(s string) string {
+	return guardLine(escapeText(s))
+}
+
+// escapeText escapes backslashes and dashes. Dashes are escaped so they're
+// rendered as a minus sign that can be searched for and copied, instead of a
+// hyphen.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	return strings.ReplaceAll(s, "-", `\-`)
+}
+
+// guardLine keeps a line starting with "." or "'" from being read as a
+// request.
+func guardLine(s string) string {
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		return `\&` + s
+	}
+
+	return s
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,298 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rdeusser/cli"
+	"github.com/rdeusser/cli/help"
+)
+
+// GenMarkdownTree writes a Markdown page for cmd and every command below it to
+// dir, along with index.md that links to every page. Pages are named like man
+// pages (e.g. "myapp-server-start.md").
+func GenMarkdownTree(cmd *cli.Command, dir string) error {
+	if err := genTree(cmd, dir, ".md", GenMarkdown); err != nil {
+		return err
+	}
+
+	return genFile(filepath.Join(dir, "index.md"), func(w io.Writer) error {
+		return GenMarkdownIndex(cmd, w)
+	})
+}
+
+// GenMarkdownIndex writes a Markdown page to w that lists cmd and every command
+// below it with links to their pages.
+func GenMarkdownIndex(cmd *cli.Command, w io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", cmd.FullName())
+
+	for _, c := range commands(cmd) {
+		model := helpModel(c)
+		indent := strings.Repeat("  ", len(strings.Fields(model.FullName))-1)
+
+		fmt.Fprintf(&sb, "%s- [%s](%s)", indent, model.FullName, pageLink(model.FullName, ".md"))
+
+		if model.Desc != "" {
+			fmt.Fprintf(&sb, ": %s", model.Desc)
+		}
+
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// GenMarkdown writes the Markdown page for cmd to w. Commands link to the
+// pages of their parent and subcommands, so pages are expected to be in the
+// same directory.
+func GenMarkdown(cmd *cli.Command, w io.Writer) error {
+	model := helpModel(cmd)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", model.FullName)
+
+	if model.Desc != "" {
+		fmt.Fprintf(&sb, "%s\n\n", model.Desc)
+	}
+
+	fmt.Fprintf(&sb, "## Usage\n\n```\n%s\n```\n", model.Usage)
+
+	if model.LongDesc != "" {
+		sb.WriteString("\n## Description\n\n")
+		writeMarkdownText(&sb, model.LongDesc)
+	}
+
+	for _, group := range model.CommandGroups {
+		fmt.Fprintf(&sb, "\n## %s\n\n", group)
+		writeMarkdownCommands(&sb, help.CommandsInGroup(model.Commands, group))
+	}
+
+	if cmds := help.CommandsInGroup(model.Commands, ""); len(cmds) > 0 {
+		sb.WriteString("\n## Commands\n\n")
+		writeMarkdownCommands(&sb, cmds)
+	}
+
+	for _, group := range model.FlagGroups {
+		fmt.Fprintf(&sb, "\n## %s\n\n", group)
+		writeMarkdownFlags(&sb, help.FlagsInGroup(model.Flags, group))
+	}
+
+	if flags := help.FlagsInGroup(model.Flags, ""); len(flags) > 0 {
+		sb.WriteString("\n## Flags\n\n")
+		writeMarkdownFlags(&sb, flags)
+	}
+
+	if len(model.GlobalFlags) > 0 {
+		sb.WriteString("\n## Global flags\n\n")
+		writeMarkdownFlags(&sb, model.GlobalFlags)
+	}
+
+	if len(model.Args) > 0 {
+		sb.WriteString("\n## Arguments\n\n")
+		sb.WriteString("| Argument | Description |\n")
+		sb.WriteString("| --- | --- |\n")
+
+		for _, arg := range model.Args {
+			name := fmt.Sprintf("<%s>", arg.Name)
+			if arg.Variadic {
+				name += "..."
+			}
+
+			fmt.Fprintf(&sb, "| `%s` | %s |\n", name, cell(arg.Desc))
+		}
+	}
+
+	if len(model.Constraints) > 0 {
+		sb.WriteString("\n## Constraints\n\n")
+
+		for _, constraint := range model.Constraints {
+			fmt.Fprintf(&sb, "- %s\n", constraint)
+		}
+	}
+
+	if len(model.Examples) > 0 {
+		sb.WriteString("\n## Examples\n")
+
+		for _, example := range model.Examples {
+			sb.WriteString("\n")
+
+			if example.Desc != "" {
+				fmt.Fprintf(&sb, "%s\n\n", example.Desc)
+			}
+
+			fmt.Fprintf(&sb, "```sh\n%s\n```\n", example.Command)
+		}
+	}
+
+	if links := seeAlsoLinks(cmd, ".md"); len(links) > 0 {
+		sb.WriteString("\n## See also\n\n")
+
+		for _, link := range links {
+			fmt.Fprintf(&sb, "- [%s](%s)", link.name, link.href)
+
+			if link.desc != "" {
+				fmt.Fprintf(&sb, ": %s", link.desc)
+			}
+
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func writeMarkdownCommands(sb *strings.Builder, commands []help.Command) {
+	sb.WriteString("| Command | Description |\n")
+	sb.WriteString("| --- | --- |\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(sb, "| [%s](%s) | %s |\n", c.Name, pageLink(c.FullName, ".md"), cell(c.Desc))
+	}
+}
+
+// writeMarkdownFlags writes a table of flags. The default and environment
+// variable columns are only included if a flag has one.
+func writeMarkdownFlags(sb *strings.Builder, flags []help.Flag) {
+	hasDefault, hasEnvVar := false, false
+
+	for _, flag := range flags {
+		hasDefault = hasDefault || flag.Default != ""
+		hasEnvVar = hasEnvVar || flag.EnvVar != ""
+	}
+
+	sb.WriteString("| Flag | Description |")
+	if hasDefault {
+		sb.WriteString(" Default |")
+	}
+
+	if hasEnvVar {
+		sb.WriteString(" Environment |")
+	}
+
+	sb.WriteString("\n| --- | --- |")
+	if hasDefault {
+		sb.WriteString(" --- |")
+	}
+
+	if hasEnvVar {
+		sb.WriteString(" --- |")
+	}
+
+	sb.WriteString("\n")
+
+	for _, flag := range flags {
+		desc := flag.Desc
+		if flag.Required {
+			desc = strings.TrimSpace(desc + " (required)")
+		}
+
+		if flag.Example != "" {
+			desc += fmt.Sprintf(" (e.g. `%s`)", flag.Example)
+		}
+
+		fmt.Fprintf(sb, "| %s | %s |", code(flagUsage(flag)), cell(desc))
+
+		if hasDefault {
+			fmt.Fprintf(sb, " %s |", code(flag.Default))
+		}
+
+		if hasEnvVar {
+			fmt.Fprintf(sb, " %s |", code(flag.EnvVar))
+		}
+
+		sb.WriteString("\n")
+	}
+}
+
+// writeMarkdownText writes a description with markup (see help.ParseText) as
+// Markdown.
+func writeMarkdownText(sb *strings.Builder, s string) {
+	for i, block := range help.ParseText(s) {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		switch block.Kind {
+		case help.BlockParagraph:
+			fmt.Fprintf(sb, "%s\n", block.Lines[0])
+		case help.BlockList:
+			for _, item := range block.Lines {
+				fmt.Fprintf(sb, "- %s\n", item)
+			}
+		case help.BlockCode:
+			fmt.Fprintf(sb, "```\n%s\n```\n", strings.Join(block.Lines, "\n"))
+		}
+	}
+}
+
+// flagUsage returns the flag as it's written on the command line (e.g.
+// "-p, --port <int>").
+func flagUsage(flag help.Flag) string {
+	names := make([]string, 0, 2)
+	if flag.Shorthand != "" {
+		names = append(names, "-"+flag.Shorthand)
+	}
+
+	if flag.Name != "" {
+		names = append(names, "--"+flag.Name)
+	}
+
+	usage := strings.Join(names, ", ")
+	if flag.Placeholder != "" {
+		usage += " " + flag.Placeholder
+	}
+
+	return usage
+}
+
+// code returns s as a code span, or "" if s is empty.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return cell("`" + s + "`")
+}
+
+// cell escapes the pipes in s so it can be used in a table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// link is a link to the page of another command.
+type link struct {
+	name string
+	href string
+	desc string
+}
+
+// seeAlsoLinks returns links to the parent and subcommands of cmd.
+func seeAlsoLinks(cmd *cli.Command, ext string) []link {
+	links := make([]link, 0)
+
+	related := cmd.Commands()
+	if parent := cmd.Parent(); parent != nil {
+		related = append([]*cli.Command{parent}, related...)
+	}
+
+	for _, c := range related {
+		model := helpModel(c)
+
+		links = append(links, link{
+			name: model.FullName,
+			href: pageLink(model.FullName, ext),
+			desc: model.Desc,
+		})
+	}
+
+	return links
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"time"
 )
 
 type EnvVar[T Value] struct {
@@ -11,6 +12,15 @@ type EnvVar[T Value] struct {
 	// Layout is the layout to use if the environment variable should be parsed
 	// as a time.Time value.
 	Layout string
+
+	// Location is the location of time.Time values without a time zone. A
+	// flag uses its own Location if it isn't set.
+	Location *time.Location
+}
+
+// envName returns the name of the environment variable.
+func (e EnvVar[T]) envName() string {
+	return e.Name
 }
 
 func (e *EnvVar[T]) Lookup() (T, error) {
@@ -21,7 +31,12 @@ func (e *EnvVar[T]) Lookup() (T, error) {
 	}
 
 	if env, ok := os.LookupEnv(e.Name); ok {
-		return parseValue[T](env, 0, e.Layout)
+		return parseValue[T](env, parseOptions{
+			time: timeOptions{
+				layouts:  timeLayouts(e.Layout, nil),
+				location: e.Location,
+			},
+		})
 	}
 
 	return result, nil
@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"fmt"
+	"os"
 	"strings"
 
 	"github.com/rdeusser/cli/internal/errors"
+	"github.com/rdeusser/cli/internal/multierror"
 	"github.com/rdeusser/cli/internal/termenv"
+	"github.com/rdeusser/cli/theme"
 )
 
 var (
@@ -26,6 +30,10 @@ var (
 	// ErrMustHaveParent indicates that an OptionSetter was given to a command
 	// that doesn't have a parent (e.g. root command).
 	ErrMustHaveParent = errors.New("command must have parent in order to use SetOptions")
+
+	// ErrFileMustHaveName indicates that an InputFile was read from without
+	// being given a name.
+	ErrFileMustHaveName = errors.New("file must have a name")
 )
 
 // ErrFlagAlreadyDefined is when you attempt to add a flag that has already been
@@ -38,11 +46,15 @@ type ErrFlagAlreadyDefined struct {
 // Error returns an error string of what flag of some type was already defined in
 // the command.
 func (e ErrFlagAlreadyDefined) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagAlreadyDefined) render(s errorStyle) string {
 	if e.Shorthand != "" {
-		return termenv.Red("-%s, --%s already defined", e.Shorthand, e.Name)
+		return s.errorf("-%s, --%s already defined", e.Shorthand, e.Name)
 	}
 
-	return termenv.Red("--%s already defined", e.Name)
+	return s.errorf("--%s already defined", e.Name)
 }
 
 // ErrArgAlreadyDefined is when you attempt to add an argument to a command that
@@ -57,7 +69,11 @@ type ErrArgAlreadyDefined struct {
 // Error returns an error string of what argument you attempted to construct
 // within the command that was already defined.
 func (e ErrArgAlreadyDefined) Error() string {
-	return termenv.Red("<%s> already defined", e.Name)
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrArgAlreadyDefined) render(s errorStyle) string {
+	return s.errorf("<%s> already defined", e.Name)
 }
 
 // ErrFlagRequired is an error describing a flag that is required.
@@ -69,11 +85,36 @@ type ErrFlagRequired struct {
 // Error returns an error string when you don't pass a required flag on the
 // command line.
 func (e ErrFlagRequired) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagRequired) render(s errorStyle) string {
 	if e.Shorthand != "" {
-		return termenv.Red("-%s, --%s is required", e.Shorthand, e.Name)
+		return s.errorf("-%s, --%s is required", e.Shorthand, e.Name)
 	}
 
-	return termenv.Red("--%s is required", e.Name)
+	return s.errorf("--%s is required", e.Name)
+}
+
+// ErrFlagMissingValue is an error describing a flag that was given without a
+// value.
+type ErrFlagMissingValue struct {
+	Name      string
+	Shorthand string
+}
+
+// Error returns an error string when a flag that takes a value is the last thing
+// on the command line.
+func (e ErrFlagMissingValue) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagMissingValue) render(s errorStyle) string {
+	if e.Shorthand != "" {
+		return s.errorf("-%s, --%s requires a value", e.Shorthand, e.Name)
+	}
+
+	return s.errorf("--%s requires a value", e.Name)
 }
 
 // ErrArgRequired is an error describing an argument that is required.
@@ -84,7 +125,160 @@ type ErrArgRequired struct {
 // Error returns an error string when you don't pass a required argument on the
 // command line.
 func (e ErrArgRequired) Error() string {
-	return termenv.Red("<%s> is required", e.Name)
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrArgRequired) render(s errorStyle) string {
+	return s.errorf("<%s> is required", e.Name)
+}
+
+// ErrFlagsMutuallyExclusive is an error describing flags that can't be used
+// together.
+type ErrFlagsMutuallyExclusive struct {
+	Names []string
+}
+
+// Error returns an error string listing the flags that were set together.
+func (e ErrFlagsMutuallyExclusive) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagsMutuallyExclusive) render(s errorStyle) string {
+	return s.errorf("%s can't be used together", joinList(e.Names, "and"))
+}
+
+// ErrFlagsRequiredTogether is an error describing flags that must be used
+// together, but only some were set.
+type ErrFlagsRequiredTogether struct {
+	Set     []string
+	Missing []string
+}
+
+// Error returns an error string listing the flags that are missing.
+func (e ErrFlagsRequiredTogether) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagsRequiredTogether) render(s errorStyle) string {
+	return s.errorf("%s must be used with %s", joinList(e.Set, "and"), joinList(e.Missing, "and"))
+}
+
+// ErrFlagsAtLeastOne is an error describing a set of flags where at least one
+// is required, but none were set.
+type ErrFlagsAtLeastOne struct {
+	Names []string
+}
+
+// Error returns an error string listing the flags that one of is required.
+func (e ErrFlagsAtLeastOne) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagsAtLeastOne) render(s errorStyle) string {
+	return s.errorf("at least one of %s is required", joinList(e.Names, "or"))
+}
+
+// ErrFlagRequiredIf is an error describing a flag that is required because
+// another flag was set to a specific value.
+type ErrFlagRequiredIf struct {
+	Name  string
+	Other string
+	Value string
+}
+
+// Error returns an error string describing the flag that is required and why.
+func (e ErrFlagRequiredIf) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagRequiredIf) render(s errorStyle) string {
+	return s.errorf("%s is required when %s is %q", e.Name, e.Other, e.Value)
+}
+
+// ErrConstraintFlagUndefined is an error describing a constraint that names a
+// flag the command doesn't have.
+type ErrConstraintFlagUndefined struct {
+	Name       string
+	Constraint string
+}
+
+// Error returns an error string with the undefined flag and the constraint that
+// names it.
+func (e ErrConstraintFlagUndefined) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrConstraintFlagUndefined) render(s errorStyle) string {
+	return s.errorf("%s isn't defined, but is used in the constraint %q", e.Name, e.Constraint)
+}
+
+// ErrPath is an error describing a path that doesn't satisfy a requirement.
+type ErrPath struct {
+	Path   string
+	Reason string
+}
+
+// Error returns an error string with the resolved path and why it was rejected.
+func (e ErrPath) Error() string {
+	return fmt.Sprintf("%s %s", e.Path, e.Reason)
+}
+
+// ErrFlagDefault is an error describing a default that couldn't be computed.
+type ErrFlagDefault struct {
+	Name string
+	Err  error
+}
+
+// Error returns an error string describing which flag's default couldn't be
+// computed and why.
+func (e ErrFlagDefault) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrFlagDefault) render(s errorStyle) string {
+	return s.errorf("unable to determine the default for %s: %s", e.Name, s.render(e.Err))
+}
+
+// Unwrap returns the error from the default function.
+func (e ErrFlagDefault) Unwrap() error {
+	return e.Err
+}
+
+// ErrJSON is an error describing a JSON value that couldn't be decoded.
+type ErrJSON struct {
+	Offset int64
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error returns an error string with where in the JSON decoding failed.
+func (e ErrJSON) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("invalid JSON: %s", e.Err)
+	}
+
+	return fmt.Sprintf("invalid JSON at line %d, column %d (offset %d): %s", e.Line, e.Column, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e ErrJSON) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownHelpTopic is an error describing a "help" argument that isn't a
+// command or help topic.
+type ErrUnknownHelpTopic struct {
+	Topic string
+}
+
+// Error returns an error string with the unknown command or topic.
+func (e ErrUnknownHelpTopic) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrUnknownHelpTopic) render(s errorStyle) string {
+	return s.errorf("error: ") + s.messagef("unknown command or help topic '%s'", e.Topic)
 }
 
 // ErrUnknown is an error describing an argument or flag that wasn't defined.
@@ -97,26 +291,154 @@ type ErrUnknown struct {
 
 // Error returns an error string for describing an argument or flag that wasn't defined.
 func (e ErrUnknown) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrUnknown) render(s errorStyle) string {
 	var lb lineBuilder
 
-	lb.Write(termenv.Red("warning: "))
-	lb.Write(termenv.BrightWhite("unknown argument '%s'", e.Arg))
+	lb.Write(s.warningf("warning: "))
+	lb.Write(s.messagef("unknown argument '%s'", e.Arg))
+	pointAt(&lb, s, e.Input, e.Arg, e.StartPos, e.EndPos)
+	lb.Flush()
+
+	return lb.String()
+}
+
+// ErrInvalidValue is an error describing a value that couldn't be parsed or
+// failed validation.
+type ErrInvalidValue struct {
+	Option   string
+	Value    string
+	Input    string
+	StartPos int
+	EndPos   int
+	Err      error
+}
+
+// Error returns an error string describing which flag or argument the invalid
+// value was given to and why it's invalid.
+func (e ErrInvalidValue) Error() string {
+	return e.render(defaultErrorStyle())
+}
+
+func (e ErrInvalidValue) render(s errorStyle) string {
+	var lb lineBuilder
+
+	lb.Write(s.errorf("error: "))
+	lb.Write(s.messagef("invalid value '%s' for %s: %s", e.Value, e.Option, s.render(e.Err)))
+
+	if e.Input != "" {
+		pointAt(&lb, s, e.Input, e.Value, e.StartPos, e.EndPos)
+	}
+
+	lb.Flush()
+
+	return lb.String()
+}
+
+// Unwrap returns the underlying parse or validation error.
+func (e ErrInvalidValue) Unwrap() error {
+	return e.Err
+}
+
+// pointAt writes input on a new line followed by a line with carets under
+// token.
+func pointAt(lb *lineBuilder, s errorStyle, input, token string, startPos, endPos int) {
 	lb.NewLine()
 	lb.Write("\t")
-	lb.Write(e.Input)
+	lb.Write(input)
 
-	idx := lastIndex(lb.CurrentLine(), e.Arg)
+	idx := lastIndex(lb.CurrentLine(), token)
+	if idx < 0 {
+		// The token isn't in the input (e.g. the implicit "true" of a bool
+		// flag), so there's nothing to point at.
+		return
+	}
 
 	lb.NewLine()
 	lb.Write(columnToSpace(idx))
 
-	count := e.EndPos - e.StartPos - 1
+	count := endPos - startPos - 1
 	if count < 0 {
 		count = 0
 	}
 
-	lb.Write(strings.Repeat(termenv.Red("^"), count))
-	lb.Flush()
+	lb.Write(strings.Repeat(s.errorf("^"), count))
+}
 
-	return lb.String()
+// errorStyle is how errors are rendered: the theme of the root command they
+// came from and whether its output is colored.
+type errorStyle struct {
+	theme theme.Theme
+	color bool
+}
+
+// defaultErrorStyle returns the style of errors that didn't come from Execute.
+// They're colored with the default theme if stdout is a terminal.
+func defaultErrorStyle() errorStyle {
+	return errorStyle{
+		theme: theme.Default(),
+		color: termenv.ColorEnabled(os.Stdout, termenv.ColorAuto),
+	}
+}
+
+// errorf returns text in the error style of the theme.
+func (s errorStyle) errorf(format string, a ...any) string {
+	return s.paint(s.theme.Error, format, a...)
+}
+
+// warningf returns text in the warning style of the theme.
+func (s errorStyle) warningf(format string, a ...any) string {
+	return s.paint(s.theme.Warning, format, a...)
+}
+
+// messagef returns text in the message style of the theme.
+func (s errorStyle) messagef(format string, a ...any) string {
+	return s.paint(s.theme.Message, format, a...)
+}
+
+func (s errorStyle) paint(style theme.Style, format string, a ...any) string {
+	text := fmt.Sprintf(format, a...)
+	if !s.color {
+		return text
+	}
+
+	return style.Paint(text)
+}
+
+// renderer is implemented by errors that are rendered with an errorStyle.
+// Their Error method uses defaultErrorStyle.
+type renderer interface {
+	render(s errorStyle) string
+}
+
+// render returns the message of err in the style. Errors collected in a
+// multierror are each rendered in the style.
+func (s errorStyle) render(err error) string {
+	switch e := err.(type) {
+	case renderer:
+		return e.render(s)
+	case *multierror.Error:
+		return e.Format(s.render)
+	}
+
+	return err.Error()
+}
+
+// styledError is an error from Execute, which is rendered in the style of the
+// root command it came from.
+type styledError struct {
+	err   error
+	style errorStyle
+}
+
+// Error returns the message of the error in the style of the root command.
+func (e styledError) Error() string {
+	return e.style.render(e.err)
+}
+
+// Unwrap returns the error that was styled.
+func (e styledError) Unwrap() error {
+	return e.err
 }
@@ -15,13 +15,17 @@ func (ac *ApplyCommand) Init() *cli.Command {
 	cmd := &cli.Command{
 		Name: "apply",
 		Desc: "Apply a resource",
+		Examples: []cli.Example{
+			{Command: "kubectl apply -f pod.json", Desc: "Apply the configuration in pod.json"},
+		},
 		Flags: cli.Flags{
 			&cli.Flag[cli.Path]{
-				Name:      "filename",
-				Shorthand: "f",
-				Desc:      "Filename to apply",
-				Value:     &ac.Filename,
-				Required:  true,
+				Name:         "filename",
+				Shorthand:    "f",
+				Desc:         "Filename to apply",
+				Value:        &ac.Filename,
+				Required:     true,
+				PathRequires: cli.PathMustExist,
 			},
 		},
 	}
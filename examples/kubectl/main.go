@@ -20,16 +20,18 @@ func (rc *RootCommand) Init() *cli.Command {
 		Desc: "kubectl controls the Kubernetes cluster manager",
 		Flags: cli.Flags{
 			&cli.Flag[bool]{
-				Name:  "debug",
-				Desc:  "Set logging level to debug",
-				Value: &rc.Debug,
+				Name:       "debug",
+				Desc:       "Set logging level to debug",
+				Value:      &rc.Debug,
+				Persistent: true,
 			},
 			&cli.Flag[string]{
-				Name:      "namespace",
-				Shorthand: "n",
-				Desc:      "Namespace to operate on",
-				Default:   "default",
-				Value:     &rc.Namespace,
+				Name:       "namespace",
+				Shorthand:  "n",
+				Desc:       "Namespace to operate on",
+				Default:    "default",
+				Value:      &rc.Namespace,
+				Persistent: true,
 			},
 			&cli.Flag[bool]{
 				Shorthand: "A",
@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// stdio is the name used on the command line to read from stdin or write to
+// stdout instead of a file.
+const stdio = "-"
+
+var (
+	_ fmt.Stringer  = (*InputFile)(nil)
+	_ io.ReadCloser = (*InputFile)(nil)
+)
+
+// InputFile is a file to read from. The file isn't opened until it's first read
+// from. A name of "-" reads from the command's input, which is stdin unless
+// changed with SetInput.
+//
+// Input files are closed automatically after the command's runners have run.
+type InputFile struct {
+	Name string
+
+	stdin io.Reader
+	file  *os.File
+}
+
+// ParseInputFile takes a file name as input and returns InputFile. It's an error
+// if the file doesn't exist.
+func ParseInputFile(name string) (InputFile, error) {
+	f := InputFile{
+		Name: name,
+	}
+
+	if f.IsStdin() {
+		return f, nil
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return f, err
+	}
+
+	if info.IsDir() {
+		return f, fmt.Errorf("%s is a directory", name)
+	}
+
+	return f, nil
+}
+
+// IsStdin returns true if the file reads from stdin.
+func (f *InputFile) IsStdin() bool {
+	return f.Name == stdio
+}
+
+// Read reads from the file, opening it on the first call.
+func (f *InputFile) Read(p []byte) (int, error) {
+	if f.IsStdin() {
+		if f.stdin == nil {
+			return os.Stdin.Read(p)
+		}
+
+		return f.stdin.Read(p)
+	}
+
+	if f.file == nil {
+		if f.Name == "" {
+			return 0, ErrFileMustHaveName
+		}
+
+		file, err := os.Open(f.Name)
+		if err != nil {
+			return 0, err
+		}
+
+		f.file = file
+	}
+
+	return f.file.Read(p)
+}
+
+// Close closes the file if it was opened. Stdin is never closed.
+func (f *InputFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+// String returns the name of the file.
+func (f InputFile) String() string {
+	return f.Name
+}
+
+var (
+	_ fmt.Stringer   = (*OutputFile)(nil)
+	_ io.WriteCloser = (*OutputFile)(nil)
+)
+
+// OutputFile is a file to write to. If no name is given or the name is "-",
+// writes go to the command's output, which is stdout unless changed with
+// SetOutput.
+//
+// Writes to a file go to a temporary file in the same directory that replaces
+// the file when it's closed, so a command that fails never leaves a partially
+// written file behind. Output files are closed automatically after the
+// command's runners have run successfully.
+type OutputFile struct {
+	Name string
+
+	stdout io.Writer
+	file   *os.File
+}
+
+// ParseOutputFile takes a file name as input and returns OutputFile. It's an
+// error if the directory the file would be written to doesn't exist.
+func ParseOutputFile(name string) (OutputFile, error) {
+	f := OutputFile{
+		Name: name,
+	}
+
+	if f.IsStdout() {
+		return f, nil
+	}
+
+	info, err := os.Stat(filepath.Dir(name))
+	if err != nil {
+		return f, err
+	}
+
+	if !info.IsDir() {
+		return f, fmt.Errorf("%s is not a directory", filepath.Dir(name))
+	}
+
+	return f, nil
+}
+
+// IsStdout returns true if the file writes to stdout.
+func (f *OutputFile) IsStdout() bool {
+	return f.Name == "" || f.Name == stdio
+}
+
+// Write writes to the file, creating it on the first call.
+func (f *OutputFile) Write(p []byte) (int, error) {
+	if f.IsStdout() {
+		if f.stdout == nil {
+			return os.Stdout.Write(p)
+		}
+
+		return f.stdout.Write(p)
+	}
+
+	if f.file == nil {
+		file, err := os.CreateTemp(filepath.Dir(f.Name), fmt.Sprintf(".%s.*.tmp", filepath.Base(f.Name)))
+		if err != nil {
+			return 0, err
+		}
+
+		f.file = file
+	}
+
+	return f.file.Write(p)
+}
+
+// Close replaces the file with everything that's been written to it. Stdout is
+// never closed.
+func (f *OutputFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+
+	tmp := f.file
+	f.file = nil
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(f.Name); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), f.Name); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Discard throws away everything that's been written to the file, leaving any
+// existing file untouched.
+func (f *OutputFile) Discard() error {
+	if f.file == nil {
+		return nil
+	}
+
+	tmp := f.file
+	f.file = nil
+
+	closeErr := tmp.Close()
+	if err := os.Remove(tmp.Name()); err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// String returns the name of the file.
+func (f OutputFile) String() string {
+	return f.Name
+}
+
+func parseInputFile[T Value](s string) (T, error) {
+	var result T
+
+	f, err := ParseInputFile(s)
+	if err != nil {
+		return result, err
+	}
+
+	switch v := any(&result).(type) {
+	case *InputFile:
+		*v = f
+	default:
+		return result, fmt.Errorf("expected type to be InputFile, got %T", result)
+	}
+
+	return result, nil
+}
+
+func parseOutputFile[T Value](s string) (T, error) {
+	var result T
+
+	f, err := ParseOutputFile(s)
+	if err != nil {
+		return result, err
+	}
+
+	switch v := any(&result).(type) {
+	case *OutputFile:
+		*v = f
+	default:
+		return result, fmt.Errorf("expected type to be OutputFile, got %T", result)
+	}
+
+	return result, nil
+}
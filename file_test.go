@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	testCases := []struct {
+		name       string
+		existing   string // contents of the output file before running
+		args       []string
+		toFile     bool // pass --out with the output file
+		run        func(in *InputFile, out *OutputFile) error
+		wantErr    bool
+		wantFile   string
+		wantStdout string
+	}{
+		{
+			name:   "Read stdin and write file",
+			args:   []string{"-f", "-"},
+			toFile: true,
+			run: func(in *InputFile, out *OutputFile) error {
+				b, err := io.ReadAll(in)
+				if err != nil {
+					return err
+				}
+
+				_, err = out.Write(b)
+				return err
+			},
+			wantFile: "from stdin",
+		},
+		{
+			name:     "Failure leaves file untouched",
+			existing: "before",
+			toFile:   true,
+			run: func(in *InputFile, out *OutputFile) error {
+				fmt.Fprint(out, "partial")
+				return fmt.Errorf("failed")
+			},
+			wantErr:  true,
+			wantFile: "before",
+		},
+		{
+			name: "Write stdout by default",
+			run: func(in *InputFile, out *OutputFile) error {
+				_, err := fmt.Fprint(out, "to stdout")
+				return err
+			},
+			wantStdout: "to stdout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "report.json")
+
+			if tc.existing != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(tc.existing), 0o600))
+			}
+
+			args := tc.args
+			if tc.toFile {
+				args = append(args, "--out", path)
+			}
+
+			var in InputFile
+			var out OutputFile
+			var stdout bytes.Buffer
+
+			cmd := &Command{
+				Name: "test",
+				Flags: Flags{
+					&Flag[InputFile]{Name: "file", Shorthand: "f", Value: &in},
+					&Flag[OutputFile]{Name: "out", Value: &out},
+				},
+			}
+			cmd.SetInput(strings.NewReader("from stdin"))
+			cmd.SetOutput(&stdout)
+
+			_, err := executeRunner(&testCommand{cmd: cmd, run: func() error {
+				return tc.run(&in, &out)
+			}}, args...)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.wantStdout, stdout.String())
+
+			if !tc.toFile {
+				return
+			}
+
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantFile, string(b))
+
+			// Nothing is left behind from writing the file.
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1)
+		})
+	}
+}
@@ -2,15 +2,44 @@ package cli
 
 import (
 	"fmt"
-	"strings"
+	"os"
+	"strconv"
+	"time"
 
-	"github.com/rdeusser/cli/internal/join"
+	"github.com/rdeusser/cli/internal/termenv"
 )
 
 var HelpFlag = &Flag[bool]{
-	Name:      "help",
-	Shorthand: "h",
-	Desc:      "Print help information",
+	Name:       "help",
+	Shorthand:  "h",
+	Desc:       "Print help information",
+	Persistent: true,
+}
+
+// newColorFlag returns the --color flag, which controls when output is
+// colored. It's added to root commands like HelpFlag. "auto" colors output if
+// it's a terminal, unless NO_COLOR is set or CLICOLOR_FORCE forces colors on.
+func newColorFlag() *Flag[string] {
+	return &Flag[string]{
+		Name:       "color",
+		Desc:       "When to color output: auto, always or never",
+		Default:    "auto",
+		Persistent: true,
+		Validate: func(value string) error {
+			_, err := termenv.ParseColorMode(value)
+			return err
+		},
+	}
+}
+
+// newNoPagerFlag returns the --no-pager flag, which turns off the pager for
+// help. It's added to root commands that have Pager set.
+func newNoPagerFlag() *Flag[bool] {
+	return &Flag[bool]{
+		Name:       "no-pager",
+		Desc:       "Don't page help output",
+		Persistent: true,
+	}
 }
 
 // Flags is a slice of flags represented as Options.
@@ -35,13 +64,62 @@ type Flag[T Value] struct {
 	Desc      string
 	Separator byte   // only applies if the value is actually many
 	Layout    string // only applies to time.Time values
+	Octal     bool   // treat a leading 0 as octal (e.g. 0755); only applies to integer values
 	Default   T
 	Value     *T
 	EnvVar    EnvVar[T]
 	Required  bool
+	Validate  func(T) error // run after the value is parsed
+	Group     string        // help section for the flag (e.g. "Networking")
+
+	// Persistent makes the flag available to every subcommand. Subcommands
+	// show it under GLOBAL FLAGS and can shadow it with a flag of the same
+	// name.
+	Persistent bool
+
+	Layouts  []string         // only applies to time.Time values; tried after Layout
+	Location *time.Location   // only applies to time.Time values
+	Now      func() time.Time // only applies to time.Time values; used for relative times
+
+	PathRequires PathRequirement // only applies to Path values
+	Extensions   []string        // only applies to Path values
+	Glob         bool            // only applies to []Path values
+	Expand       bool            // only applies to Path values; expands "~" and environment variables
+
+	// Secret hides the value of the flag everywhere it would be displayed
+	// (help, errors, String). A secret flag with a name can also be read from
+	// a file with --<name>-file, where "-" reads from stdin and "fd:N" reads
+	// from file descriptor N, so the secret never has to be on the command
+	// line.
+	Secret bool
+
+	// DefaultFunc computes the default at runtime (e.g. the current user or a
+	// value derived from another flag). It's only called if the flag wasn't
+	// set on the command line or by EnvVar, after the command line has been
+	// parsed, and its result replaces Default. Like Default, the result is
+	// checked against Validate. Use ValueOf to read other flags;
+	// their defaults are computed first if needed.
+	DefaultFunc func(flags Flags) (T, error)
+
+	// DefaultDesc describes the default in help (e.g. "the current user") so
+	// DefaultFunc never has to be called to show help.
+	DefaultDesc string
+
+	// OnSet is called every time a value is applied to the flag, so side
+	// effects (e.g. changing the log level for --debug) happen while the rest
+	// of the command line is parsed. It's called in this order:
+	//
+	//  1. From EnvVar, or Default if EnvVar isn't set, before the command line
+	//     is parsed.
+	//  2. For every occurrence of the flag on the command line, in order.
+	//  3. From DefaultFunc if the flag still hasn't been set.
+	//
+	// An error stops parsing and is reported like an invalid value.
+	OnSet func(value T, source Source) error
 
-	isSlice    bool
 	hasBeenSet bool
+	resolved   bool
+	defaultErr error
 }
 
 // Init initializes the value of a flag.
@@ -54,50 +132,199 @@ func (f *Flag[T]) Init() error {
 		return ErrInvalidShorthand
 	}
 
-	if !isZeroValue(f.Default) {
-		result, err := parseValue[T](f.Default, f.Separator, f.Layout)
-		if err != nil {
-			return err
+	f.resolved = false
+	f.defaultErr = nil
+
+	if f.EnvVar.Name != "" {
+		if env, ok := os.LookupEnv(f.EnvVar.Name); ok {
+			if err := f.set(env, f.envParseOptions(), SourceEnvVar); err != nil {
+				if f.Secret {
+					return ErrInvalidValue{
+						Option: fmt.Sprintf("$%s", f.EnvVar.Name),
+						Value:  redacted,
+						Err:    redactError(err, env),
+					}
+				}
+
+				return ErrInvalidValue{
+					Option: fmt.Sprintf("$%s", f.EnvVar.Name),
+					Value:  env,
+					Err:    err,
+				}
+			}
+
+			return nil
 		}
+	}
 
-		*f.Value = result
+	if isZeroValue(f.Default) {
+		return nil
 	}
 
-	if f.EnvVar.Name != "" {
-		result, err := parseValue[T](f.EnvVar.Name, f.Separator, f.Layout)
-		if err != nil {
-			return err
-		}
+	opts := f.parseOptions(f.Layout)
 
-		*f.Value = result
+	value, err := parseValue[T](formatValue(f.Default, f.Separator, opts.time), opts)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return f.setDefault(value)
 }
 
 // Set parses the value of s and sets the value according to the flags type.
 func (f *Flag[T]) Set(s string) error {
-	value, err := parseValue[T](s, f.Separator, f.Layout)
+	return f.set(s, f.parseOptions(f.Layout), SourceCommandLine)
+}
+
+func (f *Flag[T]) set(s string, opts parseOptions, source Source) error {
+	value, err := parseValue[T](s, opts)
 	if err != nil {
 		return err
 	}
 
+	if err := f.check(value); err != nil {
+		return err
+	}
+
 	*f.Value = value
-	f.isSlice = strings.HasPrefix(fmt.Sprint(value), "[")
 	f.hasBeenSet = true
 
+	if f.OnSet != nil {
+		return f.OnSet(value, source)
+	}
+
 	return nil
 }
 
-// String returns the string form of the flags value.
+// check checks value against Validate.
+func (f *Flag[T]) check(value T) error {
+	if f.Validate != nil {
+		return f.Validate(value)
+	}
+
+	return nil
+}
+
+// setDefault applies a default from Default or DefaultFunc. Defaults go
+// through the same checks as values from the command line, so a default that
+// fails Validate is reported like an invalid value. A
+// zero value means there's no default and isn't checked.
+func (f *Flag[T]) setDefault(value T) error {
+	if !isZeroValue(value) {
+		if err := f.check(value); err != nil {
+			return f.invalidDefault(value, err)
+		}
+	}
+
+	*f.Value = value
+
+	if f.OnSet != nil {
+		if err := f.OnSet(value, SourceDefault); err != nil {
+			return f.invalidDefault(value, err)
+		}
+	}
+
+	return nil
+}
+
+// invalidDefault wraps an error from checking a default value.
+func (f *Flag[T]) invalidDefault(value T, err error) error {
+	s := formatValue(value, f.Separator, f.parseOptions(f.Layout).time)
+	if f.Secret {
+		err = redactError(err, s)
+		s = redacted
+	}
+
+	return ErrInvalidValue{
+		Option: flagName(f.Options()),
+		Value:  s,
+		Err:    err,
+	}
+}
+
+// resolveDefault sets the value from DefaultFunc if the flag hasn't been set.
+// DefaultFunc is called at most once, so a default that depends on itself
+// through other flags sees the static Default instead of looping forever.
+func (f *Flag[T]) resolveDefault(flags Flags) error {
+	if f.resolved || f.hasBeenSet || f.DefaultFunc == nil {
+		return f.defaultErr
+	}
+
+	f.resolved = true
+
+	value, err := f.DefaultFunc(flags)
+	if err != nil {
+		f.defaultErr = ErrFlagDefault{
+			Name: flagName(f.Options()),
+			Err:  err,
+		}
+
+		return f.defaultErr
+	}
+
+	f.defaultErr = f.setDefault(value)
+
+	return f.defaultErr
+}
+
+func (f *Flag[T]) parseOptions(layout string) parseOptions {
+	return parseOptions{
+		separator: f.Separator,
+		octal:     f.Octal,
+		time: timeOptions{
+			layouts:  timeLayouts(layout, f.Layouts),
+			location: f.Location,
+			now:      f.Now,
+		},
+		path: pathOptions{
+			require:    f.PathRequires,
+			extensions: f.Extensions,
+			glob:       f.Glob,
+			expand:     f.Expand,
+		},
+	}
+}
+
+// envParseOptions returns the options EnvVar is parsed with. Its Layout and
+// Location are used over the flag's if they're set.
+func (f *Flag[T]) envParseOptions() parseOptions {
+	layout := f.EnvVar.Layout
+	if layout == "" {
+		layout = f.Layout
+	}
+
+	opts := f.parseOptions(layout)
+	if f.EnvVar.Location != nil {
+		opts.time.location = f.EnvVar.Location
+	}
+
+	return opts
+}
+
+// String returns the string form of the flags value. The value of a secret flag
+// is redacted.
 func (f *Flag[T]) String() string {
 	if f == nil || f.Value == nil {
 		return ""
 	}
 
-	// If the flag type is a slice, we have to remove the brackets that
-	// fmt.Sprint will add, and rejoin the string with the flags separator.
-	return join.WithSeparator(trimBrackets(*f.Value), f.Separator)
+	if f.Secret && !isZeroValue(*f.Value) {
+		return redacted
+	}
+
+	return f.rawString()
+}
+
+// rawString returns the string form of the flags value, even if it's a secret.
+func (f *Flag[T]) rawString() string {
+	if f == nil || f.Value == nil {
+		return ""
+	}
+
+	// If the flag type is a slice, the values are joined with the flags
+	// separator (quoting where needed) so the string parses back to the same
+	// value.
+	return formatValue(*f.Value, f.Separator, f.parseOptions(f.Layout).time)
 }
 
 // Options returns the common Options available to both flags and arguments.
@@ -108,18 +335,43 @@ func (f *Flag[T]) Options() Options {
 	}
 
 	return Options{
-		IsSlice:    f.isSlice,
-		Name:       f.Name,
-		Shorthand:  f.Shorthand,
-		Desc:       f.Desc,
-		Separator:  f.Separator,
-		Layout:     f.Layout,
-		Default:    f.Default,
-		Value:      f.Value,
-		EnvVar:     f.EnvVar,
-		Required:   f.Required,
-		HasBeenSet: f.hasBeenSet,
+		IsSlice:       isSliceValue[T](),
+		Name:          f.Name,
+		Shorthand:     f.Shorthand,
+		Desc:          f.Desc,
+		Group:         f.Group,
+		Persistent:    f.Persistent,
+		Separator:     f.Separator,
+		Layout:        f.Layout,
+		Type:          typeName[T](),
+		Default:       f.Default,
+		DefaultString: f.defaultString(),
+		DefaultDesc:   f.DefaultDesc,
+		Value:         f.Value,
+		EnvVar:        f.EnvVar,
+		Required:      f.Required,
+		Secret:        f.Secret,
+		HasBeenSet:    f.hasBeenSet,
+	}
+}
+
+// defaultString returns Default formatted for help, or "" if there isn't one.
+// Strings are quoted so empty space is visible and secrets are redacted.
+func (f *Flag[T]) defaultString() string {
+	if isZeroValue(f.Default) {
+		return ""
 	}
+
+	if f.Secret {
+		return redacted
+	}
+
+	s := formatValue(f.Default, f.Separator, f.parseOptions(f.Layout).time)
+	if typeName[T]() == "string" {
+		return strconv.Quote(s)
+	}
+
+	return s
 }
 
 // SortFlagsByName sorts flags by name.
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+func TestDefaultFunc(t *testing.T) {
+	testCases := []struct {
+		name          string
+		env           string // value of CLI_TEST_NAMESPACE
+		args          []string
+		wantNamespace string
+		wantRelease   string
+		wantCalls     int
+	}{
+		{name: "Computed", wantNamespace: "computed", wantRelease: "computed-release", wantCalls: 1},
+		{name: "Command line wins", args: []string{"--namespace", "prod"}, wantNamespace: "prod", wantRelease: "prod-release"},
+		{name: "Environment variable wins", env: "staging", wantNamespace: "staging", wantRelease: "staging-release"},
+		{name: "Help doesn't compute", args: []string{"--help"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != "" {
+				t.Setenv("CLI_TEST_NAMESPACE", tc.env)
+			}
+
+			var namespace, release string
+			var calls int
+
+			_, err := execute(&Command{
+				Name: "test",
+				Flags: Flags{
+					&Flag[string]{
+						Name:  "release",
+						Value: &release,
+						DefaultFunc: func(flags Flags) (string, error) {
+							return ValueOf[string](flags, "namespace") + "-release", nil
+						},
+						DefaultDesc: "<namespace>-release",
+					},
+					&Flag[string]{
+						Name:   "namespace",
+						Value:  &namespace,
+						EnvVar: EnvVar[string]{Name: "CLI_TEST_NAMESPACE"},
+						DefaultFunc: func(Flags) (string, error) {
+							calls++
+							return "computed", nil
+						},
+					},
+				},
+			}, tc.args...)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantNamespace, namespace)
+			assert.Equal(t, tc.wantRelease, release)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[string]{
+					Name: "user",
+					DefaultFunc: func(Flags) (string, error) {
+						return "", fmt.Errorf("no current user")
+					},
+				},
+			},
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "--user")
+		assert.Contains(t, err.Error(), "no current user")
+	})
+}
+
+func TestInvalidDefault(t *testing.T) {
+	positive := func(n int) error {
+		if n < 1 {
+			return fmt.Errorf("must be positive")
+		}
+
+		return nil
+	}
+
+	testCases := []struct {
+		name string
+		flag option
+		want string
+	}{
+		{
+			name: "Default fails Validate",
+			flag: &Flag[int]{Name: "count", Default: -1, Validate: positive},
+			want: "must be positive",
+		},
+		{
+			name: "DefaultFunc fails Validate",
+			flag: &Flag[int]{
+				Name:        "count",
+				DefaultFunc: func(Flags) (int, error) { return -1, nil },
+				Validate:    positive,
+			},
+			want: "must be positive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := execute(&Command{Name: "test", Flags: Flags{tc.flag}})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "--"+tc.flag.Options().Name)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+
+	t.Run("Not checked when set", func(t *testing.T) {
+		var count int
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[int]{Name: "count", Value: &count, DefaultFunc: func(Flags) (int, error) { return -1, nil }, Validate: positive},
+			},
+		}, "--count", "3")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+}
+
+func TestEnvVar(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		t.Setenv("CLI_TEST_PORT", "8080")
+
+		var port int
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[int]{Name: "port", Value: &port, Default: 80, EnvVar: EnvVar[int]{Name: "CLI_TEST_PORT"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, port)
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		var port int
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[int]{Name: "port", Value: &port, Default: 80, EnvVar: EnvVar[int]{Name: "CLI_TEST_UNSET_PORT"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 80, port)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv("CLI_TEST_PORT", "http")
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[int]{Name: "port", EnvVar: EnvVar[int]{Name: "CLI_TEST_PORT"}},
+			},
+		})
+		assert.True(t, errors.As(err, &ErrInvalidValue{}))
+		assert.Contains(t, err.Error(), "$CLI_TEST_PORT")
+	})
+}
+
+func TestOnSet(t *testing.T) {
+	t.Run("Order", func(t *testing.T) {
+		t.Setenv("CLI_TEST_REGION", "us-east-1")
+
+		type call struct {
+			name   string
+			value  string
+			source Source
+		}
+
+		var calls []call
+
+		record := func(name string) func(string, Source) error {
+			return func(value string, source Source) error {
+				calls = append(calls, call{name, value, source})
+				return nil
+			}
+		}
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[string]{Name: "level", Default: "info", OnSet: record("level")},
+				&Flag[string]{Name: "region", EnvVar: EnvVar[string]{Name: "CLI_TEST_REGION"}, OnSet: record("region")},
+				&Flag[string]{
+					Name:        "user",
+					DefaultFunc: func(Flags) (string, error) { return "nobody", nil },
+					OnSet:       record("user"),
+				},
+			},
+			Args: Args{
+				&Arg[string]{Name: "target", OnSet: record("target")},
+			},
+		}, "--level", "debug", "web")
+		assert.NoError(t, err)
+		assert.Equal(t, []call{
+			{"level", "info", SourceDefault},
+			{"region", "us-east-1", SourceEnvVar},
+			{"level", "debug", SourceCommandLine},
+			{"target", "web", SourceCommandLine},
+			{"user", "nobody", SourceDefault},
+		}, calls)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[bool]{
+					Name: "strict",
+					OnSet: func(bool, Source) error {
+						return fmt.Errorf("not allowed")
+					},
+				},
+			},
+		}, "--strict")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "--strict")
+		assert.Contains(t, err.Error(), "not allowed")
+	})
+}
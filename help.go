@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/rdeusser/cli/help"
+)
+
+var _ Runner = (*helpCommand)(nil)
+
+// helpCommand shows the help for a command (e.g. "app help server start") or
+// a help topic (e.g. "app help environment"), or searches the help of every
+// command (e.g. "app help --search logs"). It's added to root commands that
+// have subcommands or help topics.
+type helpCommand struct {
+	cmd    *Command
+	path   []string
+	search string
+}
+
+// Init returns the command.
+func (h *helpCommand) Init() *Command {
+	h.cmd = &Command{
+		Name: "help",
+		Desc: "Show help for a command or topic",
+		Flags: Flags{
+			&Flag[string]{
+				Name:      "search",
+				Shorthand: "s",
+				Desc:      "Search the help of every command below the given one",
+				Value:     &h.search,
+			},
+		},
+		Args: Args{
+			&Arg[[]string]{
+				Name:  "command",
+				Desc:  "Command or help topic",
+				Value: &h.path,
+			},
+		},
+	}
+
+	return h.cmd
+}
+
+// Run prints the help for the command or topic in the path, or the commands
+// below it that match the search.
+func (h *helpCommand) Run() error {
+	root := h.cmd.parent
+	target := root
+
+	for i, name := range h.path {
+		cmd, ok := target.commands[name]
+		if !ok {
+			if topic, ok := root.lookupTopic(name); ok && i == 0 && len(h.path) == 1 {
+				return root.printTopic(topic)
+			}
+
+			return ErrUnknownHelpTopic{Topic: strings.Join(h.path[:i+1], " ")}
+		}
+
+		if err := cmd.prepare(); err != nil {
+			return err
+		}
+
+		target = cmd
+	}
+
+	if h.search != "" {
+		return target.printSearch(h.cmd.Output(), h.search)
+	}
+
+	// Subcommands added before their parent was added to the root don't
+	// have the root's output, so the help is written to ours.
+	return h.cmd.printPaged(h.cmd.Output(), target.usage)
+}
+
+// lookupTopic returns the help topic with the name.
+func (c *Command) lookupTopic(name string) (HelpTopic, bool) {
+	for _, topic := range c.HelpTopics {
+		if topic.Name == name {
+			return topic, true
+		}
+	}
+
+	return HelpTopic{}, false
+}
+
+// printTopic renders a help topic with the renderer of the command, or the
+// default renderer if it can't render topics, and pages it like help.
+func (c *Command) printTopic(topic HelpTopic) error {
+	renderer, err := c.helpRenderer()
+	if err != nil {
+		return err
+	}
+
+	r, ok := renderer.(help.TopicRenderer)
+	if !ok {
+		r = c.defaultRenderer()
+	}
+
+	var sb strings.Builder
+	if err := r.RenderTopic(&sb, helpTopic(topic)); err != nil {
+		return err
+	}
+
+	return c.printPaged(c.Output(), sb.String())
+}
@@ -6,11 +6,14 @@ import (
 
 	"github.com/rdeusser/cli/internal/termenv"
 	"github.com/rdeusser/cli/tablewriter"
+	"github.com/rdeusser/cli/theme"
 )
 
 // Builder lets you build help information for cli's and services.
 type Builder struct {
 	colorize bool
+	width    int
+	theme    theme.Theme
 	sb       strings.Builder
 }
 
@@ -19,15 +22,38 @@ type Option func(*Builder)
 
 // WithNoColor disables colorization of output.
 func WithNoColor() Option {
+	return WithColor(false)
+}
+
+// WithColor turns colorization of output on or off. By default, output is
+// colored if stdout is a terminal and NO_COLOR isn't set. Commands pass the
+// decision they made from --color.
+func WithColor(enabled bool) Option {
+	return func(b *Builder) {
+		b.colorize = enabled
+	}
+}
+
+// WithWidth sets the width tables are wrapped to. Tables aren't wrapped by
+// default.
+func WithWidth(width int) Option {
+	return func(b *Builder) {
+		b.width = width
+	}
+}
+
+// WithTheme sets the styles of the builder. theme.Default is used by default.
+func WithTheme(t theme.Theme) Option {
 	return func(b *Builder) {
-		b.colorize = false
+		b.theme = t
 	}
 }
 
 // NewBuilder initializes a new builder.
 func NewBuilder(options ...Option) *Builder {
 	b := &Builder{
-		colorize: true,
+		colorize: termenv.Enabled(),
+		theme:    theme.Default(),
 		sb:       strings.Builder{},
 	}
 
@@ -38,10 +64,9 @@ func NewBuilder(options ...Option) *Builder {
 	return b
 }
 
-// Header writes yellow text to the builder.
+// Header writes text in the header style of the theme to the builder.
 func (b *Builder) Header(format string, a ...any) {
-	s := fmt.Sprintf(format, a...)
-	b.WriteString(b.Yellow(s))
+	b.WriteString(b.Style(b.theme.Header, format, a...))
 }
 
 // Text writes plan text to the builder.
@@ -57,7 +82,21 @@ func (*Builder) WithIndent(s string, indent int) string {
 
 // Table writes a table writer to the builder.
 func (b *Builder) Table(table *tablewriter.Writer) {
-	b.WriteString(table.MustRender())
+	b.WriteString(b.RenderTable(table))
+}
+
+// RenderTable returns the table as a string, wrapped to the width of the
+// builder.
+func (b *Builder) RenderTable(table *tablewriter.Writer) string {
+	table.SetMaxWidth(b.width)
+	table.SetColor(b.colorize)
+
+	return table.MustRender()
+}
+
+// Width returns the width tables are wrapped to, or 0 if they aren't.
+func (b *Builder) Width() int {
+	return b.width
 }
 
 // Newline writes a newline to the builder.
@@ -65,30 +104,32 @@ func (b *Builder) Newline() {
 	b.WriteString("\n")
 }
 
-// Yellow returns text colored as yellow. Respects the WithNoColor option.
-func (b *Builder) Yellow(format string, a ...any) string {
+// Theme returns the styles of the builder.
+func (b *Builder) Theme() theme.Theme {
+	return b.theme
+}
+
+// Style returns text in style. Respects the WithNoColor option.
+func (b *Builder) Style(style theme.Style, format string, a ...any) string {
 	if len(a) == 1 && a[0] == "" {
 		return ""
 	}
 
 	if b.colorize {
-		return termenv.Yellow(format, a...)
+		return style.Paint(fmt.Sprintf(format, a...))
 	}
 
 	return fmt.Sprintf(format, a...)
 }
 
+// Yellow returns text colored as yellow. Respects the WithNoColor option.
+func (b *Builder) Yellow(format string, a ...any) string {
+	return b.Style(theme.Style{Foreground: "3"}, format, a...)
+}
+
 // Green returns text colored as green. Respects the WithNoColor option.
 func (b *Builder) Green(format string, a ...any) string {
-	if len(a) == 1 && a[0] == "" {
-		return ""
-	}
-
-	if b.colorize {
-		return termenv.Green(format, a...)
-	}
-
-	return fmt.Sprintf(format, a...)
+	return b.Style(theme.Style{Foreground: "2"}, format, a...)
 }
 
 // Write implements io.Writer.
@@ -0,0 +1,128 @@
+package help
+
+// Command is everything needed to render help for a command. Text is already
+// formatted for display (e.g. descriptions are capitalized and secrets are
+// redacted), so renderers only need to lay it out.
+type Command struct {
+	// Name is the name of the command (e.g. "start").
+	Name string
+
+	// FullName is the name of the command including its parents (e.g.
+	// "myapp server start").
+	FullName string
+
+	// Usage is the usage line (e.g. "myapp server start [flags] <name>").
+	Usage string
+
+	// Desc is the short description of the command.
+	Desc string
+
+	// LongDesc is the long description of the command.
+	LongDesc string
+
+	// Group is the section of its parent's help the command is shown in.
+	Group string
+
+	// Commands are the subcommands of the command. Only the name,
+	// description, and group of each are set.
+	Commands []Command
+
+	// CommandGroups are the groups of the subcommands in the order they're
+	// declared.
+	CommandGroups []string
+
+	// Flags are the flags defined on the command.
+	Flags []Flag
+
+	// FlagGroups are the groups of the flags in the order they're declared.
+	FlagGroups []string
+
+	// GlobalFlags are the persistent flags inherited from parent commands.
+	GlobalFlags []Flag
+
+	// Args are the positional arguments the command accepts.
+	Args []Arg
+
+	// Constraints describe rules across flags (e.g. "--json and --yaml are
+	// mutually exclusive").
+	Constraints []string
+
+	// Examples show how the command is used.
+	Examples []Example
+
+	// Topics are the help topics of the command. Only the root command has
+	// them.
+	Topics []Topic
+}
+
+// Description returns LongDesc if the command has one, otherwise Desc.
+func (c Command) Description() string {
+	if c.LongDesc != "" {
+		return c.LongDesc
+	}
+
+	return c.Desc
+}
+
+// Flag is the help for a flag.
+type Flag struct {
+	Name        string
+	Shorthand   string
+	Placeholder string // e.g. "<string>"; empty if the flag doesn't take a value
+	Desc        string
+	Group       string // section the flag is shown in; empty for FLAGS
+	Default     string // empty if there isn't a default
+	EnvVar      string // empty if the flag can't be set from the environment
+	Example     string // an example value
+	Required    bool
+}
+
+// Arg is the help for a positional argument.
+type Arg struct {
+	Name     string
+	Desc     string
+	Required bool
+	Variadic bool // accepts many values
+}
+
+// Example is an example of how a command is used.
+type Example struct {
+	Command string // e.g. "kubectl apply -f pod.json"
+	Desc    string // what the example does
+}
+
+// Topic is a help page that isn't about a command (e.g. "environment" for the
+// environment variables a program reads).
+type Topic struct {
+	Name string // e.g. "environment"
+	Desc string // short description shown in the list of topics
+	Text string // the page; uses the same markup as descriptions
+}
+
+// CommandsInGroup returns the commands in group. An empty group returns the
+// commands without a group.
+func CommandsInGroup(commands []Command, group string) []Command {
+	result := make([]Command, 0)
+
+	for _, cmd := range commands {
+		if cmd.Group == group {
+			result = append(result, cmd)
+		}
+	}
+
+	return result
+}
+
+// FlagsInGroup returns the flags in group. An empty group returns the flags
+// without a group.
+func FlagsInGroup(flags []Flag, group string) []Flag {
+	result := make([]Flag, 0)
+
+	for _, flag := range flags {
+		if flag.Group == group {
+			result = append(result, flag)
+		}
+	}
+
+	return result
+}
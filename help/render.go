@@ -0,0 +1,415 @@
+package help
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/rdeusser/cli/tablewriter"
+)
+
+const (
+	indent  = 4
+	padding = 4
+)
+
+// Renderer renders the help for a command.
+type Renderer interface {
+	Render(w io.Writer, cmd Command) error
+}
+
+// TopicRenderer is implemented by renderers that can render help topics. Help
+// topics are rendered with DefaultRenderer if the renderer for the command
+// doesn't implement it.
+type TopicRenderer interface {
+	RenderTopic(w io.Writer, topic Topic) error
+}
+
+// RendererFunc is a function that implements Renderer.
+type RendererFunc func(w io.Writer, cmd Command) error
+
+// Render calls fn.
+func (fn RendererFunc) Render(w io.Writer, cmd Command) error {
+	return fn(w, cmd)
+}
+
+var (
+	_ Renderer      = (*DefaultRenderer)(nil)
+	_ TopicRenderer = (*DefaultRenderer)(nil)
+)
+
+// DefaultRenderer renders help with a description, usage line, and a section
+// for each of commands, help topics, flags, global flags, arguments,
+// constraints, and examples.
+type DefaultRenderer struct {
+	// Options are passed to the Builder help is rendered with.
+	Options []Option
+
+	// Width is the width descriptions and tables are wrapped to.
+	// DefaultWidth is used if it isn't set.
+	Width int
+}
+
+// Render writes the help for cmd to w.
+func (r *DefaultRenderer) Render(w io.Writer, cmd Command) error {
+	width := r.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	builder := NewBuilder(append([]Option{WithWidth(width)}, r.Options...)...)
+
+	builder.Text("%s", Text(builder, cmd.Description(), width))
+	builder.Newline()
+	builder.Newline()
+	builder.Header("USAGE:")
+	builder.Newline()
+	builder.Text(builder.WithIndent(cmd.Usage, indent))
+
+	for _, group := range cmd.CommandGroups {
+		section(builder, groupHeader(group), CommandsTable(builder, CommandsInGroup(cmd.Commands, group)))
+	}
+
+	if commands := CommandsInGroup(cmd.Commands, ""); len(commands) > 0 {
+		section(builder, "COMMANDS:", CommandsTable(builder, commands))
+	}
+
+	if len(cmd.Topics) > 0 {
+		section(builder, "HELP TOPICS:", TopicsTable(builder, cmd.Topics))
+	}
+
+	for _, group := range cmd.FlagGroups {
+		section(builder, groupHeader(group), FlagsTable(builder, FlagsInGroup(cmd.Flags, group)))
+	}
+
+	if flags := FlagsInGroup(cmd.Flags, ""); len(flags) > 0 {
+		section(builder, "FLAGS:", FlagsTable(builder, flags))
+	}
+
+	if len(cmd.GlobalFlags) > 0 {
+		section(builder, "GLOBAL FLAGS:", FlagsTable(builder, cmd.GlobalFlags))
+	}
+
+	if len(cmd.Args) > 0 {
+		builder.Newline()
+		builder.Newline()
+		builder.Header("ARGS:")
+		builder.Newline()
+		builder.Text(ArgsTable(builder, cmd.Args))
+	}
+
+	if len(cmd.Constraints) > 0 {
+		builder.Newline()
+		builder.Newline()
+		builder.Header("CONSTRAINTS:")
+
+		for _, constraint := range cmd.Constraints {
+			builder.Newline()
+			builder.Text(builder.WithIndent(constraint, indent))
+		}
+	}
+
+	if len(cmd.Examples) > 0 {
+		builder.Newline()
+		builder.Newline()
+		builder.Header("EXAMPLES:")
+		builder.Newline()
+		builder.Text(Examples(builder, cmd.Examples))
+	}
+
+	if len(cmd.Commands) > 0 {
+		builder.Newline()
+		builder.Newline()
+		builder.Text("%s", builder.Style(builder.Theme().Hint, "Use \"%s [command] --help\" for more information about a command.", cmd.FullName))
+	}
+
+	if len(cmd.Topics) > 0 {
+		builder.Newline()
+
+		if len(cmd.Commands) == 0 {
+			builder.Newline()
+		}
+
+		builder.Text("%s", builder.Style(builder.Theme().Hint, "Use \"%s help [topic]\" for more information about a topic.", cmd.FullName))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// RenderTopic writes a help topic to w. The description is shown first,
+// followed by the text.
+func (r *DefaultRenderer) RenderTopic(w io.Writer, topic Topic) error {
+	width := r.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	builder := NewBuilder(append([]Option{WithWidth(width)}, r.Options...)...)
+
+	if topic.Desc != "" {
+		builder.Header("%s", topic.Desc)
+		builder.Newline()
+		builder.Newline()
+	}
+
+	builder.Text("%s", Text(builder, topic.Text, width))
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// section writes a header followed by text.
+func section(builder *Builder, header, text string) {
+	builder.Newline()
+	builder.Newline()
+	builder.Header("%s", header)
+	builder.Newline()
+	builder.Text("%s", text)
+}
+
+// groupHeader returns the header of the section for a group.
+func groupHeader(group string) string {
+	return strings.ToUpper(group) + ":"
+}
+
+// CommandsTable returns a table of command names and descriptions.
+func CommandsTable(builder *Builder, commands []Command) string {
+	table := tablewriter.NewWriter()
+
+	for _, cmd := range commands {
+		table.AddLine(
+			tablewriter.Cell{
+				Indent:  indent,
+				Padding: padding,
+				Text:    builder.Style(builder.Theme().Command, "%s", cmd.Name),
+			},
+			tablewriter.Cell{
+				Padding: padding,
+				Text:    cmd.Desc,
+			},
+		)
+	}
+
+	return builder.RenderTable(table)
+}
+
+// TopicsTable returns a table of help topic names and descriptions.
+func TopicsTable(builder *Builder, topics []Topic) string {
+	table := tablewriter.NewWriter()
+
+	for _, topic := range topics {
+		table.AddLine(
+			tablewriter.Cell{
+				Indent:  indent,
+				Padding: padding,
+				Text:    builder.Style(builder.Theme().Command, "%s", topic.Name),
+			},
+			tablewriter.Cell{
+				Padding: padding,
+				Text:    topic.Desc,
+			},
+		)
+	}
+
+	return builder.RenderTable(table)
+}
+
+// FlagsTable returns a table of flags with their placeholders and
+// descriptions. Descriptions include the default, environment variable, and
+// whether the flag is required.
+func FlagsTable(builder *Builder, flags []Flag) string {
+	table := tablewriter.NewWriter()
+
+	for _, flag := range flags {
+		name := builder.Style(builder.Theme().Flag, "--%s", flag.Name)
+		if flag.Placeholder != "" {
+			name = strings.TrimSpace(name + " " + builder.Style(builder.Theme().Placeholder, "%s", flag.Placeholder))
+		}
+
+		suffix := ", "
+		if flag.Shorthand == "" || flag.Name == "" {
+			suffix = ""
+		}
+
+		table.AddLine(
+			tablewriter.Cell{
+				Indent: indent,
+				Text:   builder.Style(builder.Theme().Flag, "-%s", flag.Shorthand),
+				Suffix: suffix,
+			},
+			tablewriter.Cell{
+				Padding: padding,
+				Text:    name,
+			},
+			tablewriter.Cell{
+				Text: FlagDesc(builder, flag),
+			},
+		)
+
+		if flag.Example != "" {
+			table.AddLine(
+				tablewriter.Cell{
+					Indent: indent,
+				},
+				tablewriter.Cell{
+					Padding: padding,
+				},
+				tablewriter.Cell{
+					Text: fmt.Sprintf("e.g. %s", flag.Example),
+				},
+			)
+		}
+	}
+
+	return builder.RenderTable(table)
+}
+
+// FlagDesc returns the description of a flag followed by its default,
+// environment variable, and whether it's required.
+func FlagDesc(builder *Builder, flag Flag) string {
+	parts := make([]string, 0, 4)
+
+	if flag.Desc != "" {
+		parts = append(parts, flag.Desc)
+	}
+
+	if flag.Default != "" {
+		parts = append(parts, fmt.Sprintf("(default: %s)", flag.Default))
+	}
+
+	if flag.EnvVar != "" {
+		parts = append(parts, fmt.Sprintf("[env: %s]", flag.EnvVar))
+	}
+
+	if flag.Required {
+		parts = append(parts, builder.Style(builder.Theme().Required, "(required)"))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ArgsTable returns a table of argument names and descriptions.
+func ArgsTable(builder *Builder, args []Arg) string {
+	table := tablewriter.NewWriter()
+
+	for _, arg := range args {
+		text := builder.Style(builder.Theme().Flag, "<%s>", arg.Name)
+		if arg.Variadic {
+			text += builder.Style(builder.Theme().Flag, "...")
+		}
+
+		table.AddLine(
+			tablewriter.Cell{
+				Indent:  indent,
+				Padding: padding,
+				Text:    text,
+			},
+			tablewriter.Cell{
+				Text: arg.Desc,
+			},
+		)
+	}
+
+	return builder.RenderTable(table)
+}
+
+// Examples returns examples as indented command lines, each preceded by its
+// description as a comment.
+func Examples(builder *Builder, examples []Example) string {
+	parts := make([]string, 0, len(examples))
+
+	for _, example := range examples {
+		var sb strings.Builder
+
+		if example.Desc != "" {
+			sb.WriteString(builder.WithIndent("# "+example.Desc, indent))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(builder.WithIndent(builder.Style(builder.Theme().Code, "%s", example.Command), indent))
+		parts = append(parts, sb.String())
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+var _ Renderer = (*TemplateRenderer)(nil)
+
+// TemplateRenderer renders help with a text/template that's executed with a
+// Command. On top of the standard functions, templates can use:
+//
+//   - header, highlight: style text like section headers and command names
+//     with the theme.
+//   - indent: indent a string by a number of spaces.
+//   - join: join strings with a separator.
+//   - text: format a description with markup (see ParseText) for the terminal.
+//   - commandsTable, flagsTable, argsTable, topicsTable, examples: the
+//     sections from DefaultRenderer.
+//   - commandsInGroup, flagsInGroup: the commands or flags in a group.
+type TemplateRenderer struct {
+	tmpl    *template.Template
+	builder *Builder
+}
+
+// NewTemplateRenderer parses text as a template. Options are passed to the
+// Builder used by the template functions.
+func NewTemplateRenderer(text string, options ...Option) (*TemplateRenderer, error) {
+	r := &TemplateRenderer{
+		builder: NewBuilder(options...),
+	}
+
+	tmpl, err := template.New("help").Funcs(r.funcs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	r.tmpl = tmpl
+
+	return r, nil
+}
+
+// Render executes the template with cmd and writes the result to w.
+func (r *TemplateRenderer) Render(w io.Writer, cmd Command) error {
+	return r.tmpl.Execute(w, cmd)
+}
+
+func (r *TemplateRenderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"header": func(s string) string {
+			return r.builder.Style(r.builder.Theme().Header, "%s", s)
+		},
+		"highlight": func(s string) string {
+			return r.builder.Style(r.builder.Theme().Command, "%s", s)
+		},
+		"indent": func(n int, s string) string {
+			return r.builder.WithIndent(s, n)
+		},
+		"join": func(sep string, items []string) string {
+			return strings.Join(items, sep)
+		},
+		"commandsTable": func(commands []Command) string {
+			return CommandsTable(r.builder, commands)
+		},
+		"flagsTable": func(flags []Flag) string {
+			return FlagsTable(r.builder, flags)
+		},
+		"argsTable": func(args []Arg) string {
+			return ArgsTable(r.builder, args)
+		},
+		"topicsTable": func(topics []Topic) string {
+			return TopicsTable(r.builder, topics)
+		},
+		"commandsInGroup": CommandsInGroup,
+		"flagsInGroup":    FlagsInGroup,
+		"examples": func(examples []Example) string {
+			return Examples(r.builder, examples)
+		},
+		"text": func(s string) string {
+			return Text(r.builder, s, r.builder.Width())
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package help
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	cmd := Command{
+		Name:     "get",
+		FullName: "kubectl get",
+		Usage:    "kubectl get [flags] <name>",
+		Desc:     "Get a resource",
+		Flags: []Flag{
+			{Name: "namespace", Shorthand: "n", Placeholder: "<string>", Desc: "Namespace to operate on", Default: `"default"`},
+		},
+		Args: []Arg{
+			{Name: "name", Desc: "Name of the resource"},
+		},
+	}
+
+	r, err := NewTemplateRenderer(`{{.Description}}
+
+{{header "SYNOPSIS:"}}
+{{indent 2 .Usage}}
+
+{{header "OPTIONS:"}}
+{{flagsTable .Flags}}
+
+{{header "LEARN MORE:"}}
+  https://example.com/docs/{{.Name}}
+`, WithNoColor())
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, r.Render(&sb, cmd))
+
+	want := `Get a resource
+
+SYNOPSIS:
+  kubectl get [flags] <name>
+
+OPTIONS:
+    -n, --namespace <string>    Namespace to operate on (default: "default")
+
+LEARN MORE:
+  https://example.com/docs/get
+`
+
+	assert.Equal(t, want, sb.String())
+}
+
+func TestTemplateRendererParseError(t *testing.T) {
+	_, err := NewTemplateRenderer("{{.Name")
+	assert.Error(t, err)
+}
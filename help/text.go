@@ -0,0 +1,239 @@
+package help
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/rdeusser/cli/internal/wrap"
+)
+
+// DefaultWidth is the width text is wrapped to when a width isn't known.
+const DefaultWidth = 80
+
+// BlockKind is the kind of a block of text.
+type BlockKind int
+
+const (
+	// BlockParagraph is a paragraph. It has a single line that's wrapped
+	// when rendered.
+	BlockParagraph BlockKind = iota
+
+	// BlockList is a bullet list. Each line is an item.
+	BlockList
+
+	// BlockCode is preformatted text (e.g. an example). Lines are never
+	// wrapped.
+	BlockCode
+)
+
+// Block is a block of text in a description.
+type Block struct {
+	Kind  BlockKind
+	Lines []string
+}
+
+// codeSpan matches text between backticks (e.g. `--namespace`).
+var codeSpan = regexp.MustCompile("`([^`]+)`")
+
+// ParseText splits a description into blocks. The common indentation of the
+// description is removed first, so it can be written as an indented raw string
+// literal. The markup is:
+//
+//   - Blank lines separate paragraphs.
+//   - Lines starting with "- " or "* " are bullet list items. Lines indented
+//     under an item continue it.
+//   - Lines indented by at least four spaces more than the text around them,
+//     or between ``` fences, are code blocks.
+//   - Text between backticks is a code span, which is highlighted in the
+//     terminal and rendered as code in generated docs.
+func ParseText(s string) []Block {
+	lines := dedent(s)
+	blocks := make([]Block, 0)
+
+	var current *Block
+
+	flush := func() {
+		if current != nil {
+			blocks = append(blocks, *current)
+			current = nil
+		}
+	}
+
+	fenced := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+
+			if !fenced {
+				current = &Block{Kind: BlockCode}
+			}
+
+			fenced = !fenced
+		case fenced:
+			current.Lines = append(current.Lines, line)
+		case trimmed == "":
+			if current != nil && current.Kind == BlockCode {
+				current.Lines = append(current.Lines, "")
+				continue
+			}
+
+			flush()
+		case strings.HasPrefix(line, "    "):
+			if current != nil && current.Kind == BlockList {
+				current.Lines[len(current.Lines)-1] += " " + trimmed
+				continue
+			}
+
+			if current == nil || current.Kind != BlockCode {
+				flush()
+				current = &Block{Kind: BlockCode}
+			}
+
+			current.Lines = append(current.Lines, strings.TrimPrefix(line, "    "))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			if current == nil || current.Kind != BlockList {
+				flush()
+				current = &Block{Kind: BlockList}
+			}
+
+			current.Lines = append(current.Lines, strings.TrimSpace(trimmed[2:]))
+		case current != nil && current.Kind == BlockList && unicode.IsSpace(rune(line[0])):
+			current.Lines[len(current.Lines)-1] += " " + trimmed
+		default:
+			if current == nil || current.Kind != BlockParagraph {
+				flush()
+				current = &Block{Kind: BlockParagraph, Lines: []string{trimmed}}
+				continue
+			}
+
+			current.Lines[0] += " " + trimmed
+		}
+	}
+
+	flush()
+
+	// Code blocks keep blank lines inside them, but not at the end.
+	for i, block := range blocks {
+		if block.Kind == BlockCode {
+			for len(block.Lines) > 0 && strings.TrimSpace(block.Lines[len(block.Lines)-1]) == "" {
+				block.Lines = block.Lines[:len(block.Lines)-1]
+			}
+
+			blocks[i] = block
+		}
+	}
+
+	return blocks
+}
+
+// Text returns s formatted for the terminal. Paragraphs and list items are
+// wrapped to width, code blocks are indented, and code spans are highlighted.
+func Text(builder *Builder, s string, width int) string {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	// Code spans are highlighted before wrapping so the backticks don't count
+	// towards the width. Colors are carried over to the next line.
+	highlight := func(s string) string {
+		return codeSpan.ReplaceAllStringFunc(s, func(span string) string {
+			return builder.Style(builder.Theme().Code, "%s", strings.Trim(span, "`"))
+		})
+	}
+
+	blocks := ParseText(s)
+	parts := make([]string, 0, len(blocks))
+
+	for _, block := range blocks {
+		var sb strings.Builder
+
+		switch block.Kind {
+		case BlockParagraph:
+			sb.WriteString(strings.Join(Wrap(highlight(block.Lines[0]), width), "\n"))
+		case BlockList:
+			for i, item := range block.Lines {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+
+				for j, line := range Wrap(highlight(item), width-4) {
+					if j > 0 {
+						sb.WriteString("\n    ")
+					} else {
+						sb.WriteString("  - ")
+					}
+
+					sb.WriteString(line)
+				}
+			}
+		case BlockCode:
+			for i, line := range block.Lines {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+
+				if line != "" {
+					sb.WriteString(builder.WithIndent(line, 4))
+				}
+			}
+		}
+
+		parts = append(parts, sb.String())
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// Wrap splits s into lines no wider than width, breaking between words. Words
+// wider than width get a line of their own. ANSI-style escape sequences don't
+// count towards the width, and a color that's on at the end of a line is turned
+// back on at the start of the next one. Tables are wrapped the same way.
+func Wrap(s string, width int) []string {
+	return wrap.Wrap(s, width)
+}
+
+// dedent removes the indentation common to every line of s, along with blank
+// lines at the start and end. Tabs count as four spaces.
+func dedent(s string) []string {
+	lines := strings.Split(strings.ReplaceAll(s, "\t", "    "), "\n")
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	common := -1
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if common < 0 || n < common {
+			common = n
+		}
+	}
+
+	if common < 0 {
+		common = 0
+	}
+
+	for i, line := range lines {
+		if len(line) >= common {
+			line = line[common:]
+		}
+
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return lines
+}
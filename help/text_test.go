@@ -0,0 +1,87 @@
+package help
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseText(t *testing.T) {
+	text := `
+		Apply a configuration to a resource by file name or stdin.
+
+		The resource name must be specified. This resource will be
+		created if it doesn't exist yet. Supported formats:
+
+		  - JSON
+		  - YAML, including
+		    multi-document files
+
+		For example:
+
+		    kubectl apply -f pod.json
+
+		    kubectl apply -k dir/
+	`
+
+	want := []Block{
+		{Kind: BlockParagraph, Lines: []string{"Apply a configuration to a resource by file name or stdin."}},
+		{Kind: BlockParagraph, Lines: []string{"The resource name must be specified. This resource will be created if it doesn't exist yet. Supported formats:"}},
+		{Kind: BlockList, Lines: []string{"JSON", "YAML, including multi-document files"}},
+		{Kind: BlockParagraph, Lines: []string{"For example:"}},
+		{Kind: BlockCode, Lines: []string{"kubectl apply -f pod.json", "", "kubectl apply -k dir/"}},
+	}
+
+	assert.Equal(t, want, ParseText(text))
+}
+
+func TestParseTextFenced(t *testing.T) {
+	text := "Run:\n\n```\nmake build\n  make test\n```\nDone."
+
+	want := []Block{
+		{Kind: BlockParagraph, Lines: []string{"Run:"}},
+		{Kind: BlockCode, Lines: []string{"make build", "  make test"}},
+		{Kind: BlockParagraph, Lines: []string{"Done."}},
+	}
+
+	assert.Equal(t, want, ParseText(text))
+}
+
+func TestText(t *testing.T) {
+	builder := NewBuilder(WithNoColor())
+	text := `
+		Use ` + "`--namespace`" + ` to pick the namespace the resource is created in.
+
+		- first
+		- second item that is long enough to wrap onto the next line
+
+		    kubectl get pods
+	`
+
+	want := `Use --namespace to pick the namespace the
+resource is created in.
+
+  - first
+  - second item that is long enough to wrap
+    onto the next line
+
+    kubectl get pods`
+
+	assert.Equal(t, want, Text(builder, text, 44))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"a b", "c"}, Wrap("a b c", 3))
+	assert.Equal(t, []string{"averylongword", "b"}, Wrap("averylongword b", 3))
+	assert.Equal(t, []string{"\x1b[32mab\x1b[0m c"}, Wrap("\x1b[32mab\x1b[0m c", 4))
+	assert.Equal(t, []string{""}, Wrap("", 10))
+}
+
+func TestTextCodeSpans(t *testing.T) {
+	// Backticks aren't shown, so they don't count towards the width.
+	assert.Equal(t, "ab c", Text(NewBuilder(WithNoColor()), "`ab` c", 4))
+
+	// A code span that's wrapped is colored on both lines.
+	builder := NewBuilder(WithColor(true))
+	assert.Equal(t, "\x1b[32maa\x1b[0m\n\x1b[32mbb\x1b[0m c", Text(builder, "`aa bb` c", 4))
+}
@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli/help"
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+func TestHelpFlags(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	out, err := execute(&Command{
+		Name: "test",
+		Flags: Flags{
+			&Flag[string]{
+				Name:      "namespace",
+				Shorthand: "n",
+				Desc:      "Namespace to operate on",
+				Default:   "default",
+				EnvVar:    EnvVar[string]{Name: "MYAPP_NAMESPACE"},
+			},
+			&Flag[[]string]{Name: "labels", Desc: "Labels to apply", Separator: ','},
+			&Flag[int]{Name: "replicas", Desc: "Number of replicas", Required: true},
+			&Flag[string]{Name: "user", Desc: "User to run as", DefaultDesc: "the current user"},
+			&Flag[string]{Name: "token", Desc: "API token", Default: "hunter2", Secret: true},
+			&Flag[bool]{Name: "debug", Desc: "Enable debug logging"},
+		},
+	}, "--help")
+	assert.NoError(t, err)
+
+	assert.Regexp(t, `--namespace\S* <string>`, out)
+	assert.Contains(t, out, `Namespace to operate on (default: "default") [env: MYAPP_NAMESPACE]`)
+	assert.Regexp(t, `--labels\S* <string>\[,\.\.\.\]`, out)
+	assert.Regexp(t, `--replicas\S* <int>`, out)
+	assert.Contains(t, out, `(required)`)
+	assert.Contains(t, out, `User to run as (default: the current user)`)
+	assert.Contains(t, out, `API token (default: `+redacted+`)`)
+	assert.NotContains(t, out, "hunter2")
+	assert.NotRegexp(t, `--debug\S* <`, out)
+}
+
+func TestHelpRenderer(t *testing.T) {
+	t.Run("Inherited", func(t *testing.T) {
+		var model help.Command
+
+		root := &Command{
+			Name: "test",
+			HelpRenderer: help.RendererFunc(func(w io.Writer, cmd help.Command) error {
+				model = cmd
+				_, err := fmt.Fprintf(w, "custom help for %s\n", cmd.FullName)
+				return err
+			}),
+		}
+		root.AddCommands(&testCommand{
+			cmd: &Command{
+				Name: "get",
+				Desc: "get a resource.",
+				Flags: Flags{
+					&Flag[string]{Name: "output", Shorthand: "o", Desc: "output format", Default: "table"},
+				},
+				Args: Args{
+					&Arg[[]string]{Name: "names"},
+				},
+			},
+		})
+
+		out, err := execute(root, "get", "--help")
+		assert.NoError(t, err)
+		assert.Equal(t, "custom help for test get\n", out)
+		assert.Equal(t, "Get a resource", model.Desc)
+		assert.Equal(t, "test get [flags] <names>...", model.Usage)
+		assert.Contains(t, model.Flags, help.Flag{
+			Name:        "output",
+			Shorthand:   "o",
+			Placeholder: "<string>",
+			Desc:        "Output format",
+			Default:     `"table"`,
+		})
+		assert.Equal(t, []help.Arg{{Name: "names", Variadic: true}}, model.Args)
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		out, err := execute(&Command{
+			Name:         "test",
+			Desc:         "a test command",
+			HelpTemplate: "{{.Desc}}\nUSAGE: {{.Usage}}\n",
+		}, "--help")
+		assert.NoError(t, err)
+		assert.Equal(t, "A test command\nUSAGE: test [flags]\n", out)
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		_, err := execute(&Command{
+			Name:         "test",
+			HelpTemplate: "{{.Desc",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestExamples(t *testing.T) {
+	out, err := execute(&Command{
+		Name: "apply",
+		Desc: "Apply a resource",
+		LongDesc: `
+			Apply a configuration to a resource.
+
+			Supported formats:
+
+			  - JSON
+			  - YAML
+		`,
+		Examples: []Example{
+			{Command: "apply -f pod.json", Desc: "Apply the configuration in pod.json"},
+			{Command: "cat pod.json | apply -f -"},
+		},
+	}, "--help")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "Apply a configuration to a resource.\n\nSupported formats:\n\n  - JSON\n  - YAML\n\n"))
+	assert.Contains(t, out, "EXAMPLES:")
+	assert.Contains(t, out, "    # Apply the configuration in pod.json\n")
+	assert.Contains(t, out, "cat pod.json | apply -f -")
+}
+
+func TestGroups(t *testing.T) {
+	testCases := []struct {
+		name      string
+		keepOrder bool
+		sections  []string // in the order they should appear
+	}{
+		{
+			name:     "Sorted",
+			sections: []string{"MANAGEMENT COMMANDS:", "image", "volume", "COMMANDS:", "run", "NETWORKING:", "--host", "--port", "OUTPUT:", "--output", "FLAGS:", "--debug", "--help"},
+		},
+		{
+			name:      "Keep order",
+			keepOrder: true,
+			sections:  []string{"MANAGEMENT COMMANDS:", "volume", "image", "NETWORKING:", "--port", "--host"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := &Command{
+				Name:      "test",
+				KeepOrder: tc.keepOrder,
+				Flags: Flags{
+					&Flag[string]{Name: "port", Desc: "Port to listen on", Group: "Networking"},
+					&Flag[string]{Name: "output", Desc: "Output format", Group: "Output"},
+					&Flag[string]{Name: "host", Desc: "Host to listen on", Group: "Networking"},
+					&Flag[bool]{Name: "debug", Desc: "Enable debug logging"},
+				},
+			}
+			root.AddCommands(
+				&testCommand{cmd: &Command{Name: "volume", Desc: "Manage volumes", Group: "Management Commands"}},
+				&testCommand{cmd: &Command{Name: "run", Desc: "Run a container"}},
+				&testCommand{cmd: &Command{Name: "image", Desc: "Manage images", Group: "Management Commands"}},
+			)
+
+			out, err := execute(root, "--help")
+			assert.NoError(t, err)
+
+			last := -1
+
+			for _, section := range tc.sections {
+				idx := strings.Index(out[last+1:], section)
+				if !assert.GreaterOrEqual(t, idx, 0, "%s should come after the previous section", section) {
+					return
+				}
+
+				last += idx + 1
+			}
+		})
+	}
+}
+
+func TestHelpWrapping(t *testing.T) {
+	t.Setenv("COLUMNS", "60")
+
+	out, err := execute(&Command{
+		Name: "test",
+		Flags: Flags{
+			&Flag[string]{
+				Name: "namespace",
+				Desc: "The namespace to operate on, which must already exist in the cluster",
+			},
+		},
+	}, "--help")
+	assert.NoError(t, err)
+
+	for _, line := range strings.Split(out, "\n") {
+		assert.LessOrEqual(t, len(stripANSI(line)), 60, line)
+	}
+
+	assert.Contains(t, out, "\n                              ")
+}
+
+func TestHelpCommand(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		want     string   // the whole output, if set
+		contains []string // parts of the output
+		wantErr  string
+	}{
+		{
+			name: "Root help lists help and topics",
+			args: []string{"--help"},
+			contains: []string{
+				"Show help for a command or topic",
+				"HELP TOPICS:",
+				"environment    Environment variables",
+				`Use "test help [topic]" for more information about a topic.`,
+			},
+		},
+		{name: "Command path", args: []string{"help", "server", "start"}, contains: []string{"test server start [flags]"}},
+		{name: "No path", args: []string{"help"}, contains: []string{"test [flags] [command]"}},
+		{name: "Topic", args: []string{"help", "environment"}, want: "Environment variables\n\nSet TEST_DEBUG to enable debug logging."},
+		{name: "Unknown", args: []string{"help", "server", "stop"}, wantErr: "unknown command or help topic 'server stop'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := &Command{
+				Name: "test",
+				HelpTopics: []HelpTopic{
+					{
+						Name: "environment",
+						Desc: "environment variables",
+						Text: "Set `TEST_DEBUG` to enable debug logging.",
+					},
+				},
+			}
+
+			server := &Command{Name: "server", Desc: "Manage servers"}
+			server.AddCommands(&testCommand{
+				cmd: &Command{Name: "start", Desc: "Start a server"},
+			})
+
+			root.AddCommands(&testCommand{cmd: server})
+
+			out, err := execute(root, tc.args...)
+			if tc.wantErr != "" {
+				assert.True(t, errors.As(err, &ErrUnknownHelpTopic{}))
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			out = stripANSI(out)
+			if tc.want != "" {
+				assert.Equal(t, tc.want, out)
+			}
+
+			for _, want := range tc.contains {
+				assert.Contains(t, out, want)
+			}
+		})
+	}
+
+	t.Run("Not added without subcommands", func(t *testing.T) {
+		out, err := execute(&Command{Name: "test"}, "--help")
+		assert.NoError(t, err)
+		assert.NotContains(t, out, "Show help for a command or topic")
+	})
+}
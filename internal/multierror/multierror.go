@@ -25,17 +25,25 @@ func (e *Error) ErrorOrNil() error {
 		return nil
 	}
 
-	return fmt.Errorf(e.Error())
+	return &Error{errors: e.errors}
 }
 
 func (e *Error) Error() string {
+	return e.Format(func(err error) string {
+		return err.Error()
+	})
+}
+
+// Format returns the message of the error where each error is formatted with
+// fn.
+func (e *Error) Format(fn func(error) string) string {
 	if len(e.errors) == 1 {
-		return fmt.Sprintf("1 error occurred:\n\t* %s\n", e.errors[0])
+		return fmt.Sprintf("1 error occurred:\n\t* %s\n", fn(e.errors[0]))
 	}
 
 	errors := make([]string, 0)
 	for _, err := range e.errors {
-		errors = append(errors, fmt.Sprintf("* %s", err))
+		errors = append(errors, fmt.Sprintf("* %s", fn(err)))
 	}
 
 	return fmt.Sprintf("%d errors occurred:\n\t%s\n", len(errors), strings.Join(errors, "\n\t"))
@@ -0,0 +1,93 @@
+package termenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode controls when output is colored.
+type ColorMode int
+
+const (
+	// ColorAuto colors output if it's a terminal, unless the environment
+	// says otherwise (see ColorEnabled).
+	ColorAuto ColorMode = iota
+
+	// ColorAlways always colors output.
+	ColorAlways
+
+	// ColorNever never colors output.
+	ColorNever
+)
+
+// String returns the name of the mode as it's passed to --color.
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAuto:
+		return "auto"
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	default:
+		return fmt.Sprintf("ColorMode(%d)", int(m))
+	}
+}
+
+// ParseColorMode parses "auto", "always" or "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	for _, m := range []ColorMode{ColorAuto, ColorAlways, ColorNever} {
+		if s == m.String() {
+			return m, nil
+		}
+	}
+
+	return ColorAuto, fmt.Errorf("unknown color mode %q", s)
+}
+
+// ColorEnabled returns true if output written to w should be colored in mode.
+// In ColorAuto mode, output is colored if w is a terminal, but NO_COLOR turns
+// colors off and CLICOLOR_FORCE turns them on even when w isn't a terminal
+// (see https://no-color.org and https://bixense.com/clicolors). A dumb
+// terminal is never colored.
+func ColorEnabled(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return IsTerminal(w)
+}
+
+// IsTerminal returns true if w writes to a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(fder)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Enabled returns true if stdout would be colored in ColorAuto mode. It's for
+// output that doesn't belong to a command; commands decide from --color.
+func Enabled() bool {
+	return ColorEnabled(os.Stdout, ColorAuto)
+}
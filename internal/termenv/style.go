@@ -0,0 +1,77 @@
+package termenv
+
+import (
+	"os"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// Profile is the set of colors a terminal supports.
+type Profile = termenv.Profile
+
+// Attrs are the attributes of styled text. Colors are ANSI colors ("0" to
+// "15"), 256 colors ("16" to "255") or true colors ("#rrggbb"), and are
+// converted to the closest color the profile supports. Empty colors are left
+// as the terminal's default.
+type Attrs struct {
+	Foreground string
+	Background string
+	Bold       bool
+	Faint      bool
+	Italic     bool
+	Underline  bool
+}
+
+// ColorProfile returns the profile used for colored output. Terminals that set
+// COLORTERM to "truecolor" or "24bit" get true colors, terminals with
+// "256color" in TERM get 256 colors and everything else gets the 16 ANSI
+// colors. Whether output is colored at all is decided by Enabled.
+func ColorProfile() Profile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return termenv.TrueColor
+	}
+
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return termenv.ANSI256
+	}
+
+	return termenv.ANSI
+}
+
+// Style returns s with attrs, whether or not colors are on.
+func Style(s string, attrs Attrs) string {
+	if s == "" {
+		return s
+	}
+
+	profile := ColorProfile()
+	style := termenv.String(s)
+
+	if attrs.Foreground != "" {
+		style = style.Foreground(profile.Color(attrs.Foreground))
+	}
+
+	if attrs.Background != "" {
+		style = style.Background(profile.Color(attrs.Background))
+	}
+
+	if attrs.Bold {
+		style = style.Bold()
+	}
+
+	if attrs.Faint {
+		style = style.Faint()
+	}
+
+	if attrs.Italic {
+		style = style.Italic()
+	}
+
+	if attrs.Underline {
+		style = style.Underline()
+	}
+
+	return style.String()
+}
@@ -0,0 +1,48 @@
+package termenv
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is the width used when the width of the terminal can't be
+// determined (e.g. output is piped to a file).
+const DefaultWidth = 80
+
+// fder is implemented by writers backed by a file descriptor (e.g. *os.File).
+type fder interface {
+	Fd() uintptr
+}
+
+// Width returns the width of the terminal w writes to. The COLUMNS environment
+// variable overrides it. If w isn't a terminal, DefaultWidth is returned.
+func Width(w io.Writer) int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	if f, ok := w.(fder); ok {
+		if width, _, ok := terminalSize(f.Fd()); ok && width > 0 {
+			return width
+		}
+	}
+
+	return DefaultWidth
+}
+
+// Height returns the height of the terminal w writes to. The LINES environment
+// variable overrides it. If w isn't a terminal, 0 is returned.
+func Height(w io.Writer) int {
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+
+	if f, ok := w.(fder); ok {
+		if _, height, ok := terminalSize(f.Fd()); ok && height > 0 {
+			return height
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package termenv
+
+// terminalSize always fails since there's no way to get the size of a
+// terminal on this platform.
+func terminalSize(fd uintptr) (width, height int, ok bool) {
+	return 0, 0, false
+}
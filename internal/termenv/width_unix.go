@@ -0,0 +1,17 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package termenv
+
+import "golang.org/x/sys/unix"
+
+// terminalSize returns the number of columns and rows of the terminal fd
+// refers to.
+func terminalSize(fd uintptr) (width, height int, ok bool) {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return int(ws.Col), int(ws.Row), true
+}
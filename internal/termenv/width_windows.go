@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package termenv
+
+import "golang.org/x/sys/windows"
+
+// terminalSize returns the number of columns and rows of the console fd
+// refers to.
+func terminalSize(fd uintptr) (width, height int, ok bool) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, 0, false
+	}
+
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, true
+}
@@ -0,0 +1,141 @@
+package wrap
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	escapeStartRune = rune(27) // \x1b
+	escapeStopRune  = 'm'
+)
+
+// resetSequence turns off every ANSI-style color and style.
+const resetSequence = "\x1b[0m"
+
+// Wrap splits s into lines no wider than width, breaking between words. Words
+// wider than width get a line of their own. ANSI-style escape sequences don't
+// count towards the width and are never split. If a line ends while a color
+// is on, the color is reset at the end of the line and turned back on at the
+// start of the next one.
+func Wrap(s string, width int) []string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	lines := make([]string, 0)
+	active := ""
+
+	var line strings.Builder
+
+	lineWidth := 0
+
+	for _, word := range words {
+		w := Width(word)
+
+		if lineWidth > 0 && lineWidth+1+w > width {
+			if active != "" {
+				line.WriteString(resetSequence)
+			}
+
+			lines = append(lines, line.String())
+			line.Reset()
+			line.WriteString(active)
+			lineWidth = 0
+		}
+
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+
+		line.WriteString(word)
+		lineWidth += w
+		active = activeSequences(active, word)
+	}
+
+	return append(lines, line.String())
+}
+
+// Width returns the number of runes in s, not counting ANSI-style escape
+// sequences.
+func Width(s string) int {
+	width := 0
+	isEscapeSequence := false
+
+	for _, r := range s {
+		switch {
+		case r == escapeStartRune:
+			isEscapeSequence = true
+		case isEscapeSequence:
+			if r == escapeStopRune {
+				isEscapeSequence = false
+			}
+		default:
+			width++
+		}
+	}
+
+	return width
+}
+
+// splitWords splits s around spaces that aren't part of an escape sequence.
+func splitWords(s string) []string {
+	words := make([]string, 0)
+
+	var word strings.Builder
+
+	isEscapeSequence := false
+
+	for _, r := range s {
+		switch {
+		case r == escapeStartRune:
+			isEscapeSequence = true
+		case isEscapeSequence:
+			if r == escapeStopRune {
+				isEscapeSequence = false
+			}
+		case unicode.IsSpace(r):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+
+			continue
+		}
+
+		word.WriteRune(r)
+	}
+
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	return words
+}
+
+// activeSequences returns the escape sequences that are still on after s,
+// given the ones that were on before it.
+func activeSequences(active, s string) string {
+	for {
+		start := strings.IndexRune(s, escapeStartRune)
+		if start < 0 {
+			return active
+		}
+
+		end := strings.IndexRune(s[start:], escapeStopRune)
+		if end < 0 {
+			return active
+		}
+
+		seq := s[start : start+end+1]
+		if seq == resetSequence || seq == "\x1b[m" {
+			active = ""
+		} else {
+			active += seq
+		}
+
+		s = s[start+end+1:]
+	}
+}
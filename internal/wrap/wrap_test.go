@@ -0,0 +1,29 @@
+package wrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	testCases := []struct {
+		name  string
+		s     string
+		width int
+		want  []string
+	}{
+		{"Fits", "a b c", 10, []string{"a b c"}},
+		{"Wraps", "aaa bbb ccc", 7, []string{"aaa bbb", "ccc"}},
+		{"Long word", "aaaaaaaaaa b", 5, []string{"aaaaaaaaaa", "b"}},
+		{"Escapes don't count", "\x1b[32maaa\x1b[0m bbb", 7, []string{"\x1b[32maaa\x1b[0m bbb"}},
+		{"Color carries over", "\x1b[33maaa bbb\x1b[0m ccc", 3, []string{"\x1b[33maaa\x1b[0m", "\x1b[33mbbb\x1b[0m", "ccc"}},
+		{"Empty", "", 10, []string{""}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Wrap(tc.s, tc.width))
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package cli
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+var _ option = (*JSONFlag[any])(nil)
+
+// JSONFlag is a flag whose value is JSON decoded into T with encoding/json. T
+// is usually a struct, map or slice (e.g. --patch '{"spec":{"replicas":3}}').
+//
+// A value starting with "@" is read from a file instead (e.g. --patch
+// @patch.json), where "@-" reads from the command's input.
+//
+// Unlike Flag, a JSONFlag can't be secret. Its value is shown in help and
+// errors, so don't use it for values that contain credentials.
+type JSONFlag[T any] struct {
+	Name      string
+	Shorthand string
+	Desc      string
+	Example   string // shown in help instead of a summary of T
+	Default   T
+	Value     *T
+	EnvVar    EnvVar[string] // environment variable holding the JSON
+	Required  bool
+	Strict    bool          // reject fields that aren't in T
+	Validate  func(T) error // run after the value is decoded
+	Group     string        // help section for the flag
+
+	// Persistent makes the flag available to every subcommand. See
+	// Flag.Persistent.
+	Persistent bool
+
+	// OnSet is called every time a value is applied to the flag. See
+	// Flag.OnSet for the order.
+	OnSet func(value T, source Source) error
+
+	stdin      io.Reader
+	hasBeenSet bool
+}
+
+// Init initializes the value of a flag.
+func (f *JSONFlag[T]) Init() error {
+	if f.Value == nil {
+		f.Value = new(T)
+	}
+
+	if len(f.Shorthand) > 1 {
+		return ErrInvalidShorthand
+	}
+
+	if !reflect.ValueOf(&f.Default).Elem().IsZero() {
+		// The default is copied through JSON so maps and slices in it aren't
+		// shared with the value.
+		b, err := json.Marshal(f.Default)
+		if err != nil {
+			return err
+		}
+
+		result, err := f.decode(b)
+		if err != nil {
+			return err
+		}
+
+		*f.Value = result
+	}
+
+	if f.EnvVar.Name != "" {
+		if env, ok := os.LookupEnv(f.EnvVar.Name); ok {
+			if err := f.set(env, SourceEnvVar); err != nil {
+				return ErrInvalidValue{
+					Option: fmt.Sprintf("$%s", f.EnvVar.Name),
+					Value:  env,
+					Err:    err,
+				}
+			}
+
+			return nil
+		}
+	}
+
+	if !reflect.ValueOf(&f.Default).Elem().IsZero() && f.OnSet != nil {
+		if err := f.OnSet(*f.Value, SourceDefault); err != nil {
+			return ErrInvalidValue{
+				Option: flagName(f.Options()),
+				Value:  f.String(),
+				Err:    err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Set decodes s, or the file named after a leading "@", and sets the value.
+func (f *JSONFlag[T]) Set(s string) error {
+	return f.set(s, SourceCommandLine)
+}
+
+func (f *JSONFlag[T]) set(s string, source Source) error {
+	data := []byte(s)
+
+	if strings.HasPrefix(s, "@") {
+		b, err := f.readFile(strings.TrimPrefix(s, "@"))
+		if err != nil {
+			return err
+		}
+
+		data = b
+	}
+
+	value, err := f.decode(data)
+	if err != nil {
+		return err
+	}
+
+	if f.Validate != nil {
+		if err := f.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	*f.Value = value
+	f.hasBeenSet = true
+
+	if f.OnSet != nil {
+		return f.OnSet(value, source)
+	}
+
+	return nil
+}
+
+func (f *JSONFlag[T]) readFile(name string) ([]byte, error) {
+	if name != stdio {
+		return os.ReadFile(name)
+	}
+
+	if f.stdin == nil {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return io.ReadAll(f.stdin)
+}
+
+func (f *JSONFlag[T]) decode(data []byte) (T, error) {
+	var result T
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if f.Strict {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(&result); err != nil {
+		return result, jsonError(data, err)
+	}
+
+	if rest := bytes.TrimSpace(data[dec.InputOffset():]); len(rest) > 0 {
+		return result, jsonErrorAt(data, dec.InputOffset(), errors.New("unexpected data after the JSON value"))
+	}
+
+	return result, nil
+}
+
+func (f *JSONFlag[T]) setInput(r io.Reader) {
+	f.stdin = r
+}
+
+// String returns the value of the flag encoded as JSON.
+func (f *JSONFlag[T]) String() string {
+	if f == nil || f.Value == nil || reflect.ValueOf(f.Value).Elem().IsZero() {
+		return ""
+	}
+
+	b, err := json.Marshal(*f.Value)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// Options returns the common Options available to both flags and arguments.
+func (f *JSONFlag[T]) Options() Options {
+	example := f.Example
+	if example == "" {
+		example = jsonSummary(reflect.TypeOf(f.Value).Elem(), nil)
+	}
+
+	return Options{
+		Name:          f.Name,
+		Shorthand:     f.Shorthand,
+		Desc:          f.Desc,
+		Group:         f.Group,
+		Persistent:    f.Persistent,
+		Example:       example,
+		Type:          "json",
+		Default:       f.Default,
+		DefaultString: f.defaultString(),
+		Value:         f.Value,
+		EnvVar:        f.EnvVar,
+		Required:      f.Required,
+		HasBeenSet:    f.hasBeenSet,
+	}
+}
+
+// defaultString returns Default encoded as JSON, or "" if there isn't one.
+func (f *JSONFlag[T]) defaultString() string {
+	if reflect.ValueOf(&f.Default).Elem().IsZero() {
+		return ""
+	}
+
+	b, err := json.Marshal(f.Default)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// inputSetter is implemented by options that read from the command's input.
+type inputSetter interface {
+	setInput(r io.Reader)
+}
+
+// jsonError wraps err with the line and column of where decoding data failed.
+func jsonError(data []byte, err error) error {
+	var offset int64
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	case errors.Is(err, io.EOF):
+		return ErrJSON{Err: errors.New("empty value")}
+	default:
+		return ErrJSON{Err: err}
+	}
+
+	return jsonErrorAt(data, offset, err)
+}
+
+// jsonErrorAt wraps err with the line and column of offset in data. Offsets from
+// encoding/json are just past the byte that couldn't be decoded, which makes
+// them the column of that byte.
+func jsonErrorAt(data []byte, offset int64, err error) error {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n') - 1
+	if column < 1 {
+		column = 1
+	}
+
+	return ErrJSON{
+		Offset: offset,
+		Line:   line,
+		Column: column,
+		Err:    err,
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// jsonSummary returns a short description of the JSON t is decoded from (e.g.
+// {"spec": {"replicas": int}}).
+func jsonSummary(t reflect.Type, seen []reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return "string"
+	}
+
+	for _, s := range seen {
+		if s == t {
+			return "..."
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+
+		return fmt.Sprintf("[%s]", jsonSummary(t.Elem(), append(seen, t)))
+	case reflect.Map:
+		return fmt.Sprintf("{string: %s}", jsonSummary(t.Elem(), append(seen, t)))
+	case reflect.Struct:
+		fields := make([]string, 0, t.NumField())
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Name
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				tagName, _, _ := strings.Cut(tag, ",")
+				if tagName == "-" {
+					continue
+				}
+
+				if tagName != "" {
+					name = tagName
+				}
+			}
+
+			fields = append(fields, fmt.Sprintf("%q: %s", name, jsonSummary(field.Type, append(seen, t))))
+		}
+
+		return fmt.Sprintf("{%s}", strings.Join(fields, ", "))
+	default:
+		return "any"
+	}
+}
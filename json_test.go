@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+type testPatch struct {
+	Spec struct {
+		Replicas int               `json:"replicas"`
+		Labels   map[string]string `json:"labels,omitempty"`
+	} `json:"spec"`
+}
+
+func TestJSONFlag(t *testing.T) {
+	testCases := []struct {
+		name         string
+		arg          string
+		file         string // written to a file that's passed as @file
+		wantReplicas int
+		wantErr      []string
+		wantJSONErr  bool
+		wantLine     int
+		wantColumn   int
+	}{
+		{name: "Inline", arg: `{"spec":{"replicas":3}}`, wantReplicas: 3},
+		{name: "From file", file: `{"spec":{"replicas":4}}`, wantReplicas: 4},
+		{name: "From stdin", arg: "@-", wantReplicas: 5},
+		{
+			name:        "Syntax error",
+			arg:         `{"spec":{"replicas":}}`,
+			wantErr:     []string{"--patch"},
+			wantJSONErr: true,
+			wantLine:    1,
+			wantColumn:  21,
+		},
+		{name: "Type error", arg: `{"spec":{"replicas":"3"}}`, wantErr: []string{"offset"}, wantJSONErr: true},
+		{name: "Unknown field", arg: `{"spec":{"replica":3}}`, wantErr: []string{"replica"}},
+		{name: "Trailing data", arg: `{"spec":{}} {}`, wantErr: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			arg := tc.arg
+			if tc.file != "" {
+				name := filepath.Join(t.TempDir(), "patch.json")
+				assert.NoError(t, os.WriteFile(name, []byte(tc.file), 0o600))
+				arg = "@" + name
+			}
+
+			var p testPatch
+
+			cmd := &Command{
+				Name: "test",
+				Flags: Flags{
+					&JSONFlag[testPatch]{Name: "patch", Strict: true, Value: &p},
+				},
+			}
+			cmd.SetInput(strings.NewReader(`{"spec":{"replicas":5}}`))
+
+			_, err := execute(cmd, "--patch", arg)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantReplicas, p.Spec.Replicas)
+				return
+			}
+
+			if !assert.Error(t, err) {
+				return
+			}
+
+			for _, want := range tc.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+
+			if tc.wantJSONErr {
+				var jsonErr ErrJSON
+				assert.True(t, errors.As(err, &jsonErr))
+
+				if tc.wantLine > 0 {
+					assert.Equal(t, tc.wantLine, jsonErr.Line)
+					assert.Equal(t, tc.wantColumn, jsonErr.Column)
+				}
+			}
+		})
+	}
+
+	t.Run("String", func(t *testing.T) {
+		value := map[string]int{"a": 1}
+		flag := &JSONFlag[map[string]int]{Name: "m", Value: &value}
+		assert.Equal(t, `{"a":1}`, flag.String())
+	})
+
+	t.Run("Value of", func(t *testing.T) {
+		var p testPatch
+
+		cmd := &Command{
+			Name: "test",
+			Flags: Flags{
+				&JSONFlag[testPatch]{Name: "patch", Value: &p},
+			},
+		}
+		assert.NoError(t, cmd.Flags[0].Set(`{"spec":{"replicas":6}}`))
+
+		assert.Equal(t, 6, JSONValueOf[testPatch](cmd.Flags, "patch").Spec.Replicas)
+		assert.Equal(t, map[string]int(nil), JSONValueOf[map[string]int](cmd.Flags, "patch"), "wrong type")
+		assert.Equal(t, "", ValueOf[string](cmd.Flags, "patch"), "not a Flag")
+	})
+
+	t.Run("EnvVar", func(t *testing.T) {
+		t.Setenv("TEST_PATCH", `{"spec":{"replicas":7}}`)
+
+		var p testPatch
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&JSONFlag[testPatch]{Name: "patch", Value: &p, EnvVar: EnvVar[string]{Name: "TEST_PATCH"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, p.Spec.Replicas)
+
+		t.Setenv("TEST_PATCH", `{"spec":`)
+
+		_, err = execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&JSONFlag[testPatch]{Name: "patch", EnvVar: EnvVar[string]{Name: "TEST_PATCH"}},
+			},
+		}, "--help")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "$TEST_PATCH")
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		t.Setenv("COLUMNS", "200")
+
+		out, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&JSONFlag[testPatch]{Name: "patch", Value: &testPatch{}},
+			},
+		}, "--help")
+		assert.NoError(t, err)
+		assert.Contains(t, out, `e.g. {"spec": {"replicas": int, "labels": {string: string}}}`)
+	})
+}
@@ -1,5 +1,7 @@
 package cli
 
+import "fmt"
+
 type OptionSetter interface {
 	SetOptions(flags Flags) error
 }
@@ -11,16 +13,73 @@ type option interface {
 	Options() Options
 }
 
+// Source is where the value of a flag or argument came from.
+type Source int
+
+const (
+	// SourceDefault is a value from Default or DefaultFunc.
+	SourceDefault Source = iota
+
+	// SourceEnvVar is a value from an environment variable.
+	SourceEnvVar
+
+	// SourceCommandLine is a value from the command line, including secrets
+	// read from a file.
+	SourceCommandLine
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceEnvVar:
+		return "environment variable"
+	case SourceCommandLine:
+		return "command line"
+	default:
+		return fmt.Sprintf("Source(%d)", int(s))
+	}
+}
+
 type Options struct {
-	IsSlice    bool
-	Name       string
-	Shorthand  string
-	Desc       string
-	Separator  byte
-	Layout     string
-	Default    any
-	Value      any
-	EnvVar     any
-	Required   bool
-	HasBeenSet bool
+	IsSlice       bool
+	Name          string
+	Shorthand     string
+	Desc          string
+	Group         string
+	Example       string
+	Separator     byte
+	Layout        string
+	Type          string
+	Default       any
+	DefaultString string
+	DefaultDesc   string
+	Value         any
+	EnvVar        any
+	Required      bool
+	Persistent    bool
+	Secret        bool
+	HasBeenSet    bool
+}
+
+// defaulter is implemented by options with defaults that are computed after
+// the command line has been parsed.
+type defaulter interface {
+	resolveDefault(flags Flags) error
+}
+
+// rawStringer is implemented by options whose String redacts the value. The
+// raw string is only for comparisons and must never be displayed.
+type rawStringer interface {
+	rawString() string
+}
+
+// rawString returns the string form of the value of opt without redacting it.
+func rawString(opt option) string {
+	if r, ok := opt.(rawStringer); ok {
+		return r.rawString()
+	}
+
+	return opt.String()
 }
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rdeusser/cli/internal/termenv"
+)
+
+// defaultPager is used when PAGER isn't set.
+const defaultPager = "less -R"
+
+// setPager decides whether help is paged from --no-pager in args. Like
+// --color, it's decided before anything is parsed since help can be printed
+// before --no-pager is reached.
+func (c *Command) setPager(args []string) {
+	c.noPager = false
+	noPager := newNoPagerFlag()
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if isFlag(arg) && matchesFlag(arg, noPager) {
+			c.noPager = true
+		}
+	}
+}
+
+// printPaged writes s to w, through the pager if the root command has Pager
+// set, w is a terminal and s doesn't fit on it. If the pager can't be started,
+// s is written to w directly.
+func (c *Command) printPaged(w io.Writer, s string) error {
+	if c.pagerEnabled() && termenv.IsTerminal(w) && !fits(s, termenv.Height(w)) {
+		if err := runPager(pagerArgs(), w, s); err == nil {
+			return nil
+		}
+	}
+
+	_, err := io.WriteString(w, s)
+
+	return err
+}
+
+// pagerEnabled returns true if the root command has Pager set and it hasn't
+// been turned off by setting NO_PAGER, passing --no-pager or setting PAGER to
+// "" or "cat".
+func (c *Command) pagerEnabled() bool {
+	root := c.root()
+
+	return root.Pager && !root.noPager && os.Getenv("NO_PAGER") == "" && pagerArgs() != nil
+}
+
+// fits returns true if s fits on a terminal with height lines. Everything
+// fits if the height isn't known.
+func fits(s string, height int) bool {
+	return height <= 0 || strings.Count(s, "\n")+1 <= height
+}
+
+// pagerArgs returns the pager command from PAGER, or "less -R" if it isn't
+// set. PAGER is split into words like a shell would, so arguments can be
+// quoted (e.g. PAGER='less --prompt="help (q to quit)"'). It returns nil if
+// PAGER is set to "" or "cat", which means there's no pager, or if it can't be
+// split.
+func pagerArgs() []string {
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = defaultPager
+	}
+
+	args, err := shellWords(pager)
+	if err != nil || len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+
+	return args
+}
+
+// shellWords splits s into words like a POSIX shell, without expanding
+// anything. Words are separated by spaces, tabs and newlines. Text in single
+// quotes is taken as-is, and a backslash escapes the next character outside
+// of quotes and a double quote, backslash, dollar sign or backquote inside
+// double quotes.
+func shellWords(s string) ([]string, error) {
+	words := make([]string, 0)
+
+	var sb strings.Builder
+
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, sb.String())
+				sb.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("missing closing quote in %q", s)
+			}
+
+			sb.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			closed := false
+
+			for i++; i < len(s); i++ {
+				if s[i] == '"' {
+					closed = true
+					break
+				}
+
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+
+				sb.WriteByte(s[i])
+			}
+
+			if !closed {
+				return nil, fmt.Errorf("missing closing quote in %q", s)
+			}
+
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			sb.WriteByte(s[i])
+			inWord = true
+		default:
+			sb.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, sb.String())
+	}
+
+	return words, nil
+}
+
+// runPager writes s to the pager in args, which writes to w. It only returns an
+// error if the pager couldn't be started. Once it's running, it may have shown
+// some of s already, so writing s again would show it twice.
+func runPager(args []string, w io.Writer, s string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Writing fails if the pager exits without reading everything (e.g. it's
+	// quit early), which isn't an error.
+	_, _ = io.WriteString(stdin, s)
+	_ = stdin.Close()
+
+	// The pager reports its own errors (e.g. an unknown option) on stderr.
+	_ = cmd.Wait()
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPager(t *testing.T) {
+	t.Run("Args", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			pager *string
+			want  []string
+		}{
+			{"Unset", nil, []string{"less", "-R"}},
+			{"Set", stringPtr("more -s"), []string{"more", "-s"}},
+			{"Extra spaces", stringPtr("  less\t-R "), []string{"less", "-R"}},
+			{"Double quotes", stringPtr(`less --prompt="help (q to quit)"`), []string{"less", "--prompt=help (q to quit)"}},
+			{"Single quotes", stringPtr(`'/opt/my pager/bin/less' -R`), []string{"/opt/my pager/bin/less", "-R"}},
+			{"Escapes", stringPtr(`less --prompt=a\ b "\"q\""`), []string{"less", "--prompt=a b", `"q"`}},
+			{"Empty quotes", stringPtr(`less ""`), []string{"less", ""}},
+			{"Missing quote", stringPtr(`less "-R`), nil},
+			{"Empty", stringPtr(""), nil},
+			{"Cat", stringPtr("cat"), nil},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Setenv("PAGER", "")
+
+				if tc.pager == nil {
+					os.Unsetenv("PAGER")
+				} else {
+					os.Setenv("PAGER", *tc.pager)
+				}
+
+				assert.Equal(t, tc.want, pagerArgs())
+			})
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		if _, err := exec.LookPath("cat"); err != nil {
+			t.Skip("cat isn't available")
+		}
+
+		var out bytes.Buffer
+
+		assert.NoError(t, runPager([]string{"cat"}, &out, "some help\n"))
+		assert.Equal(t, "some help\n", out.String())
+	})
+
+	t.Run("Not started", func(t *testing.T) {
+		var out bytes.Buffer
+
+		assert.Error(t, runPager([]string{"cli-test-missing-pager"}, &out, "some help\n"))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("Exits with an error", func(t *testing.T) {
+		if _, err := exec.LookPath("false"); err != nil {
+			t.Skip("false isn't available")
+		}
+
+		// The pager may have shown the help before failing, so it isn't
+		// written again.
+		var out bytes.Buffer
+
+		assert.NoError(t, runPager([]string{"false"}, &out, "some help\n"))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("Not a terminal", func(t *testing.T) {
+		var out bytes.Buffer
+
+		cmd := &Command{Name: "test", Pager: true}
+		assert.NoError(t, cmd.printPaged(&out, "some help\n"))
+		assert.Equal(t, "some help\n", out.String())
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			pager   bool
+			args    []string
+			env     map[string]string
+			enabled bool
+		}{
+			{name: "Pager", pager: true, args: []string{"--help"}, enabled: true},
+			{name: "Pager not set", args: []string{"--help"}},
+			{name: "--no-pager", pager: true, args: []string{"--help", "--no-pager"}},
+			{name: "NO_PAGER", pager: true, args: []string{"--help"}, env: map[string]string{"NO_PAGER": "1"}},
+			{name: "PAGER=cat", pager: true, args: []string{"--help"}, env: map[string]string{"PAGER": "cat"}},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Setenv("NO_PAGER", "")
+				t.Setenv("PAGER", "less -R")
+
+				for k, v := range tc.env {
+					t.Setenv(k, v)
+				}
+
+				cmd := &Command{Name: "test", Pager: tc.pager}
+
+				_, err := execute(cmd, tc.args...)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.enabled, cmd.pagerEnabled())
+			})
+		}
+	})
+
+	t.Run("Fits", func(t *testing.T) {
+		assert.True(t, fits("a\nb", 2))
+		assert.False(t, fits("a\nb\nc", 2))
+		assert.True(t, fits("a\nb\nc", 0))
+	})
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
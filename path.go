@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+// PathRequirement is a requirement that a Path must satisfy. Requirements can
+// be combined (e.g. PathMustExist|PathMustBeFile).
+type PathRequirement int
+
+const (
+	// PathMustExist requires the path to exist.
+	PathMustExist PathRequirement = 1 << iota
+
+	// PathMustNotExist requires the path to not exist.
+	PathMustNotExist
+
+	// PathMustBeFile requires the path to be a file if it exists.
+	PathMustBeFile
+
+	// PathMustBeDir requires the path to be a directory if it exists.
+	PathMustBeDir
+
+	// PathMustBeReadable requires the path to be readable if it exists.
+	PathMustBeReadable
+
+	// PathMustBeWritable requires the path to be writable if it exists, or
+	// the directory it would be created in to be writable if it doesn't.
+	PathMustBeWritable
+)
+
+// Has returns true if r includes req.
+func (r PathRequirement) Has(req PathRequirement) bool {
+	return r&req != 0
+}
+
+// pathOptions controls how paths are parsed and checked.
+type pathOptions struct {
+	require    PathRequirement
+	extensions []string
+	glob       bool
+	expand     bool
+}
+
+var _ fmt.Stringer = (*Path)(nil)
+
+// Path is a path on the filesystem.
+type Path struct {
+	Path   string
+	Ext    string
+	IsDir  bool
+	Exists bool
+	Mode   os.FileMode
+	Size   int64
+}
+
+// ParsePath takes a path as input, cleans it, and returns Path.
+func ParsePath(path string) (Path, error) {
+	p := Path{
+		Path:   filepath.Clean(path),
+		IsDir:  false,
+		Exists: false,
+	}
+
+	p.Ext = filepath.Ext(p.Path)
+
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		// File doesn't exist, so return early.
+		if errors.Is(err, os.ErrNotExist) {
+			return p, nil
+		}
+
+		return p, err
+	}
+
+	p.IsDir = info.IsDir()
+	p.Exists = true
+	p.Mode = info.Mode()
+	p.Size = info.Size()
+
+	return p, nil
+}
+
+// Check returns an error if the path doesn't satisfy every requirement in req.
+// If extensions are provided, the path must have one of them.
+func (p Path) Check(req PathRequirement, extensions ...string) error {
+	switch {
+	case req.Has(PathMustExist) && !p.Exists:
+		return ErrPath{Path: p.Path, Reason: "does not exist"}
+	case req.Has(PathMustNotExist) && p.Exists:
+		return ErrPath{Path: p.Path, Reason: "already exists"}
+	case req.Has(PathMustBeFile) && p.Exists && p.IsDir:
+		return ErrPath{Path: p.Path, Reason: "is a directory"}
+	case req.Has(PathMustBeDir) && p.Exists && !p.IsDir:
+		return ErrPath{Path: p.Path, Reason: "is not a directory"}
+	case req.Has(PathMustBeReadable) && p.Exists && !isReadable(p):
+		return ErrPath{Path: p.Path, Reason: "is not readable"}
+	case req.Has(PathMustBeWritable) && !isWritable(p):
+		return ErrPath{Path: p.Path, Reason: "is not writable"}
+	}
+
+	if len(extensions) > 0 && !hasExtension(p, extensions) {
+		return ErrPath{
+			Path:   p.Path,
+			Reason: fmt.Sprintf("must have one of the extensions %s", strings.Join(extensions, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// String returns the path provided to Path.
+func (p Path) String() string {
+	return p.Path
+}
+
+// expandPath expands a leading "~" to the user's home directory and any
+// environment variables in path.
+func expandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path, err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// expandGlob returns the paths matching pattern. Patterns without any special
+// characters are returned as-is so paths that don't exist yet can still be
+// given.
+func expandGlob(pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, ErrPath{Path: pattern, Reason: "does not match any files"}
+	}
+
+	return matches, nil
+}
+
+func hasExtension(p Path, extensions []string) bool {
+	for _, ext := range extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+
+		if strings.EqualFold(p.Ext, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isReadable(p Path) bool {
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return false
+	}
+
+	file.Close()
+
+	return true
+}
+
+// isWritable returns true if the path can be written to, or if it doesn't
+// exist, created in its directory. Nothing is written to check.
+func isWritable(p Path) bool {
+	if p.Exists {
+		return canWrite(p.Path)
+	}
+
+	return canWrite(filepath.Dir(p.Path))
+}
+
+func parsePath[T Value](s string, separator byte, opts pathOptions) (T, error) {
+	var result T
+
+	values := make([]Path, 0)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
+
+	if opts.expand {
+		for i, v := range slice {
+			expanded, err := expandPath(v)
+			if err != nil {
+				return result, err
+			}
+
+			slice[i] = expanded
+		}
+	}
+
+	if opts.glob {
+		if _, ok := any(&result).(*[]Path); ok {
+			matches := make([]string, 0)
+
+			for _, pattern := range slice {
+				m, err := expandGlob(pattern)
+				if err != nil {
+					return result, err
+				}
+
+				matches = append(matches, m...)
+			}
+
+			slice = matches
+		}
+	}
+
+	for _, v := range slice {
+		p, err := ParsePath(v)
+		if err != nil {
+			return result, err
+		}
+
+		if err := p.Check(opts.require, opts.extensions...); err != nil {
+			return result, err
+		}
+
+		values = append(values, p)
+	}
+
+	switch v := any(&result).(type) {
+	case *Path:
+		*v = values[0]
+	case *[]Path:
+		*v = values
+	default:
+		return result, fmt.Errorf("expected type to be Path, got %T", result)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,29 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package cli
+
+import "os"
+
+// canWrite returns true if the file or directory at path can be written to.
+// There's no access(2) on this platform, so a directory is writable unless
+// it's read-only and a file is opened for writing without changing it.
+func canWrite(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if info.IsDir() {
+		return info.Mode().Perm()&0o200 != 0
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+
+	file.Close()
+
+	return true
+}
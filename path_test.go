@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+func TestPath(t *testing.T) {
+	testCases := []struct {
+		name      string
+		arg       string
+		wantPaths []string // relative to the directory
+		wantErr   string   // relative to the directory
+	}{
+		{name: "Glob and env expansion", arg: "$TEST_PATH_DIR/*.yaml", wantPaths: []string{"a.yaml", "b.yaml"}},
+		{name: "Wrong extension", arg: "$TEST_PATH_DIR/c.json", wantErr: "c.json must have one of the extensions yaml"},
+		{name: "Must exist", arg: "$TEST_PATH_DIR/missing.yaml", wantErr: "missing.yaml does not exist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range []string{"a.yaml", "b.yaml", "c.json"} {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600))
+			}
+
+			t.Setenv("TEST_PATH_DIR", dir)
+
+			var paths []Path
+
+			_, err := execute(&Command{
+				Name: "test",
+				Flags: Flags{
+					&Flag[[]Path]{
+						Name:         "file",
+						Separator:    ',',
+						Value:        &paths,
+						PathRequires: PathMustExist | PathMustBeFile,
+						Extensions:   []string{"yaml"},
+						Glob:         true,
+						Expand:       true,
+					},
+				},
+			}, "--file", tc.arg)
+			if tc.wantErr != "" {
+				var ierr ErrInvalidValue
+				assert.True(t, errors.As(err, &ierr))
+				assert.Equal(t, "--file", ierr.Option)
+				assert.EqualError(t, ierr.Err, filepath.Join(dir, tc.wantErr))
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if !assert.Len(t, paths, len(tc.wantPaths)) {
+				return
+			}
+
+			for i, name := range tc.wantPaths {
+				assert.Equal(t, filepath.Join(dir, name), paths[i].Path)
+				assert.True(t, paths[i].Exists)
+				assert.Equal(t, int64(len(name)), paths[i].Size)
+				assert.Equal(t, os.FileMode(0o600), paths[i].Mode.Perm())
+			}
+		})
+	}
+
+	t.Run("Must be writable", func(t *testing.T) {
+		out := t.TempDir()
+
+		var p Path
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[Path]{Name: "out", Value: &p, PathRequires: PathMustBeWritable},
+			},
+		}, "--out", filepath.Join(out, "new.txt"))
+		assert.NoError(t, err)
+		assert.False(t, p.Exists)
+
+		// Checking doesn't create anything.
+		entries, err := os.ReadDir(out)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+
+		_, err = execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[Path]{Name: "out", PathRequires: PathMustBeWritable},
+			},
+		}, "--out", filepath.Join(out, "missing", "new.txt"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Home expansion", func(t *testing.T) {
+		home, err := os.UserHomeDir()
+		assert.NoError(t, err)
+
+		var p Path
+
+		_, err = execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[Path]{Name: "out", Value: &p, Expand: true},
+			},
+		}, "--out", "~/foo")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, "foo"), p.Path)
+	})
+
+	t.Run("No expansion", func(t *testing.T) {
+		t.Setenv("TEST_PATH_DIR", t.TempDir())
+
+		var p Path
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[Path]{Name: "out", Value: &p},
+			},
+		}, "--out", "~/$TEST_PATH_DIR")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join("~", "$TEST_PATH_DIR"), p.Path)
+	})
+
+	t.Run("Ext", func(t *testing.T) {
+		p, err := ParsePath("config.yaml/")
+		assert.NoError(t, err)
+		assert.Equal(t, "config.yaml", p.Path)
+		assert.Equal(t, ".yaml", p.Ext)
+	})
+}
@@ -0,0 +1,12 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package cli
+
+import "golang.org/x/sys/unix"
+
+// canWrite returns true if the file or directory at path can be written to.
+// For a directory, that means files can be created in it.
+func canWrite(path string) bool {
+	return unix.Access(path, unix.W_OK) == nil
+}
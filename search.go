@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rdeusser/cli/help"
+	"github.com/rdeusser/cli/internal/termenv"
+)
+
+// How much a match in each part of a command counts towards its rank. Matches
+// in the name are worth the most since that's usually what's being looked for.
+const (
+	weightName     = 10
+	weightDesc     = 5
+	weightFlag     = 3
+	weightExample  = 2
+	weightLongDesc = 2
+)
+
+// snippetWidth is about how much text is shown around a match.
+const snippetWidth = 60
+
+// searchResult is a command that matched a search.
+type searchResult struct {
+	cmd     *Command
+	score   int
+	snippet string
+}
+
+// searchField is a part of a command that's searched.
+type searchField struct {
+	text     string
+	prefix   string // shown before the snippet (e.g. the name of a flag)
+	keywords string // searched, but not shown in the snippet
+	weight   int
+}
+
+// matches returns true if the field contains term.
+func (f searchField) matches(term string) bool {
+	return strings.Contains(strings.ToLower(f.text), term) || strings.Contains(strings.ToLower(f.keywords), term)
+}
+
+// search returns the commands below c that match every word in query, best
+// matches first. Names, descriptions, long descriptions, examples and flags
+// are searched without regard to case.
+func (c *Command) search(query string) []searchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	results := make([]searchResult, 0)
+
+	_ = c.Visit(func(cmd *Command) error {
+		if cmd.isHiddenFromSearch() {
+			return nil
+		}
+
+		if result, ok := cmd.match(terms); ok {
+			results = append(results, result)
+		}
+
+		return nil
+	}, VisitDescendants)
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+
+		return results[i].cmd.FullName() < results[j].cmd.FullName()
+	})
+
+	return results
+}
+
+// isHiddenFromSearch returns true for the help and __spec commands.
+func (c *Command) isHiddenFromSearch() bool {
+	switch c.runner.(type) {
+	case *helpCommand, *specCommand:
+		return true
+	default:
+		return false
+	}
+}
+
+// match scores the command against terms. The snippet is taken from the field
+// that contributes the most to the score, other than the name, falling back to
+// the description.
+func (c *Command) match(terms []string) (searchResult, bool) {
+	fields := c.searchFields()
+	scores := make([]int, len(fields))
+
+	for _, term := range terms {
+		found := false
+
+		for i, field := range fields {
+			if field.matches(term) {
+				scores[i] += field.weight
+				found = true
+			}
+		}
+
+		if !found {
+			return searchResult{}, false
+		}
+	}
+
+	result := searchResult{cmd: c, snippet: formatDesc(c.Desc)}
+	best := 0
+
+	for i, score := range scores {
+		result.score += score
+
+		// The name is shown in the results anyway, so it doesn't need a
+		// snippet.
+		if i > 0 && score > best {
+			best = score
+			result.snippet = fields[i].prefix + snippet(fields[i].text, firstMatch(fields[i].text, terms))
+		}
+	}
+
+	return result, true
+}
+
+// firstMatch returns the term that's found first in s.
+func firstMatch(s string, terms []string) string {
+	s = strings.ToLower(s)
+	first, idx := "", -1
+
+	for _, term := range terms {
+		if i := strings.Index(s, term); i >= 0 && (idx < 0 || i < idx) {
+			first, idx = term, i
+		}
+	}
+
+	return first
+}
+
+// searchFields returns the parts of the command that are searched. The name
+// is always first.
+func (c *Command) searchFields() []searchField {
+	fields := []searchField{
+		{text: c.Name, weight: weightName},
+		{text: c.Desc, weight: weightDesc},
+		{text: c.LongDesc, weight: weightLongDesc},
+	}
+
+	for _, example := range c.Examples {
+		fields = append(fields, searchField{
+			text:   example.Desc + " " + example.Command,
+			weight: weightExample,
+		})
+	}
+
+	for _, flag := range c.localFlags() {
+		opt := flag.Options()
+		fields = append(fields, searchField{
+			text:     formatDesc(opt.Desc),
+			prefix:   flagName(opt) + ": ",
+			keywords: opt.Name,
+			weight:   weightFlag,
+		})
+	}
+
+	return fields
+}
+
+// snippet returns the text around the first match of term in s, with "..."
+// where text was cut off.
+func snippet(s, term string) string {
+	s = strings.Join(strings.Fields(s), " ")
+
+	idx := strings.Index(strings.ToLower(s), term)
+	if idx < 0 || len(s) <= snippetWidth {
+		return s
+	}
+
+	start := idx - (snippetWidth-len(term))/2
+	if start < 0 {
+		start = 0
+	}
+
+	// A term longer than the snippet would put start after the match.
+	if start > idx {
+		start = idx
+	}
+
+	end := start + snippetWidth
+	if end > len(s) {
+		end = len(s)
+		start = end - snippetWidth
+	}
+
+	// Don't cut words in half.
+	if start > 0 && start < idx {
+		if i := strings.IndexByte(s[start:idx], ' '); i >= 0 {
+			start += i + 1
+		}
+	}
+
+	if end < len(s) {
+		if i := strings.LastIndexByte(s[idx:end], ' '); i > len(term) {
+			end = idx + i
+		}
+	}
+
+	text := s[start:end]
+	if start > 0 {
+		text = "..." + text
+	}
+
+	if end < len(s) {
+		text += "..."
+	}
+
+	return text
+}
+
+// printSearch writes the results of searching for query to w.
+func (c *Command) printSearch(w io.Writer, query string) error {
+	results := c.search(query)
+	if len(results) == 0 {
+		_, err := fmt.Fprintf(w, "No commands match %q.\n", query)
+		return err
+	}
+
+	commands := make([]help.Command, 0, len(results))
+	for _, result := range results {
+		commands = append(commands, help.Command{
+			Name: result.cmd.FullName(),
+			Desc: result.snippet,
+		})
+	}
+
+	builder := help.NewBuilder(help.WithWidth(termenv.Width(w)), help.WithColor(c.ColorEnabled()), help.WithTheme(c.getTheme()))
+	builder.Header("RESULTS:")
+	builder.Newline()
+	builder.Text("%s", help.CommandsTable(builder, commands))
+	builder.Newline()
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
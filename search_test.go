@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		want     string     // the whole output, if set
+		lines    [][]string // parts of each line of the results, if set
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "Ranked",
+			args: []string{"help", "--search", "logs"},
+			lines: [][]string{
+				{"RESULTS:"},
+				{"test server logs", "Print the logs of a server"},
+				{"test server start", `with "test server logs" once`},
+			},
+		},
+		{
+			name:     "Every term must match",
+			args:     []string{"help", "-s", "new logs"},
+			contains: []string{"--follow: Keep printing new logs"},
+			excludes: []string{"test server start"},
+		},
+		{name: "Examples", args: []string{"help", "--search", "number"}, contains: []string{"test version"}},
+		{name: "Below a command", args: []string{"help", "server", "--search", "version"}, want: "No commands match \"version\".\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := &Command{Name: "test"}
+
+			server := &Command{Name: "server", Desc: "Manage servers"}
+			server.AddCommands(
+				&testCommand{
+					cmd: &Command{
+						Name: "logs",
+						Desc: "Print the logs of a server",
+						Flags: Flags{
+							&Flag[bool]{Name: "follow", Shorthand: "f", Desc: "Keep printing new logs"},
+						},
+					},
+				},
+				&testCommand{
+					cmd: &Command{
+						Name: "start",
+						Desc: "Start a server",
+						LongDesc: `
+							Starts a server in the background. Its output can be read
+							with "test server logs" once it's running.
+						`,
+					},
+				},
+			)
+
+			root.AddCommands(
+				&testCommand{cmd: server},
+				&testCommand{
+					cmd: &Command{
+						Name: "version",
+						Desc: "Print the version",
+						Examples: []Example{
+							{Command: "test version --short", Desc: "Only the number"},
+						},
+					},
+				},
+			)
+
+			out, err := execute(root, tc.args...)
+			assert.NoError(t, err)
+
+			if tc.want != "" {
+				assert.Equal(t, tc.want, out)
+			}
+
+			out = stripANSI(out)
+
+			if tc.lines != nil {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				if assert.Len(t, lines, len(tc.lines)) {
+					for i, parts := range tc.lines {
+						for _, part := range parts {
+							assert.Contains(t, lines[i], part)
+						}
+					}
+				}
+			}
+
+			for _, want := range tc.contains {
+				assert.Contains(t, out, want)
+			}
+
+			for _, exclude := range tc.excludes {
+				assert.NotContains(t, out, exclude)
+			}
+		})
+	}
+
+	t.Run("Term longer than a snippet", func(t *testing.T) {
+		word := strings.Repeat("a", 70)
+		root := &Command{Name: "test"}
+		root.AddCommands(&testCommand{
+			cmd: &Command{
+				Name:     "start",
+				Desc:     "Start a server",
+				LongDesc: "Starts a server. Set the " + word + " option to run it in the background.",
+			},
+		})
+
+		out, err := execute(root, "help", "--search", word)
+		assert.NoError(t, err)
+		assert.Contains(t, stripANSI(out), "test start")
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	s := "The quick brown fox jumps over the lazy dog and then keeps running until it reaches the river bank."
+
+	assert.Equal(t, "short text", snippet("short   text", "text"))
+	assert.Equal(t, "The quick brown fox jumps over the lazy dog and then keeps...", snippet(s, "quick"))
+	assert.Equal(t, "...dog and then keeps running until it reaches the river bank.", snippet(s, "river"))
+
+	long := strings.Repeat("x", 70)
+	assert.Equal(t, "..."+long[:60]+"...", snippet("See "+long+" for details.", long))
+}
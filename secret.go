@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rdeusser/cli/internal/errors"
+	"github.com/rdeusser/cli/internal/join"
+)
+
+// redacted replaces the value of secret flags wherever it would be displayed.
+const redacted = "********"
+
+// secretFileSuffix is appended to the name of a secret flag to read its value
+// from a file (e.g. --token-file).
+const secretFileSuffix = "-file"
+
+// lookupSecretFile returns the secret flag that arg reads from a file (e.g.
+// returns --token for --token-file).
+func (flags Flags) lookupSecretFile(arg string) option {
+	name := trimDash(arg)
+	if !strings.HasPrefix(arg, "--") || !strings.HasSuffix(name, secretFileSuffix) {
+		return nil
+	}
+
+	flag := flags.Lookup(strings.TrimSuffix(name, secretFileSuffix))
+	if flag == nil || !flag.Options().Secret || flag.Options().Name == "" {
+		return nil
+	}
+
+	return flag
+}
+
+// readSecretFile reads the value of a secret flag from name. A name of "-"
+// reads from the command's input and "fd:N" reads from file descriptor N,
+// which is closed afterwards. Descriptors 0, 1 and 2 are rejected so stdin,
+// stdout and stderr are never closed; "-" reads from stdin. Trailing newlines
+// are removed.
+func (c *Command) readSecretFile(name string) (string, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	switch {
+	case name == stdio:
+		b, err = io.ReadAll(c.Input())
+	case strings.HasPrefix(name, "fd:"):
+		fd, perr := strconv.Atoi(strings.TrimPrefix(name, "fd:"))
+		if perr != nil || fd < 0 {
+			return "", fmt.Errorf("invalid file descriptor: %s", name)
+		}
+
+		if fd <= 2 {
+			return "", fmt.Errorf("file descriptor %d is stdin, stdout or stderr; use %q to read from stdin", fd, stdio)
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+		if file == nil {
+			return "", fmt.Errorf("invalid file descriptor: %s", name)
+		}
+		defer file.Close()
+
+		b, err = io.ReadAll(file)
+	default:
+		b, err = os.ReadFile(name)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// redactedInput returns the input the command was called with where the value
+// of every secret flag in the command tree is redacted. Flags from the whole
+// tree are used so that a secret is still hidden when it's given to the wrong
+// command. The value after a flag that isn't defined anywhere is redacted too,
+// since it could be a misspelled secret flag (e.g. --tokne).
+func (c *Command) redactedInput() string {
+	if c.stmt == nil {
+		return ""
+	}
+
+	flags := c.root().treeFlags()
+	args := make([]string, 0, len(c.stmt.Arguments))
+	redactNext := false
+	redactValue := false
+
+	for _, arg := range c.stmt.Arguments {
+		name := arg.String()
+
+		if redactNext || (redactValue && !isFlag(name)) {
+			args = append(args, redacted)
+			redactNext, redactValue = false, false
+
+			continue
+		}
+
+		redactValue = false
+
+		if isFlag(name) {
+			flag := flags.Lookup(name)
+			redactNext = flag != nil && flag.Options().Secret
+			redactValue = flag == nil && flags.lookupSecretFile(name) == nil
+		}
+
+		args = append(args, name)
+	}
+
+	return join.Args(args)
+}
+
+// treeFlags returns every flag of the command and its children.
+func (c *Command) treeFlags() Flags {
+	flags := append(make(Flags, 0, len(c.Flags)), c.Flags...)
+
+	for _, cmd := range c.commands {
+		flags = append(flags, cmd.treeFlags()...)
+	}
+
+	return flags
+}
+
+// root returns the top-most parent of the command.
+func (c *Command) root() *Command {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root
+}
+
+// redactError replaces every occurrence of value in the message of err. Parse
+// errors (e.g. from strconv) tend to include the value that couldn't be parsed.
+func redactError(err error, value string) error {
+	if err == nil || value == "" {
+		return err
+	}
+
+	return errors.New(strings.ReplaceAll(err.Error(), value, redacted))
+}
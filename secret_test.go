@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecret(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      []string
+		file      string // written to a file whose name is appended to args
+		wantToken string
+		wantOut   string
+		wantErr   []string
+	}{
+		{name: "Unknown command", args: []string{"logn", "--token", "abc123"}, wantErr: []string{"--token " + redacted}},
+		{name: "Unknown flag", args: []string{"login", "--tokne", "abc123"}, wantErr: []string{"--tokne " + redacted}},
+		{
+			// Only values are redacted, not the flags that follow.
+			name:    "Unknown flag followed by a flag",
+			args:    []string{"login", "--tokne", "--port", "abc123"},
+			wantErr: []string{"--tokne --port " + redacted},
+		},
+		{name: "Invalid value", args: []string{"login", "--port", "abc123"}, wantErr: []string{}},
+		{name: "Read from stdin", args: []string{"login", "--token-file", "-"}, wantToken: "abc123"},
+		{name: "Read from file", args: []string{"login", "--token-file"}, file: "def456\n", wantToken: "def456"},
+		{name: "Stdin descriptor", args: []string{"login", "--token-file", "fd:0"}, wantErr: []string{`use "-" to read from stdin`}},
+		{name: "Stdout descriptor", args: []string{"login", "--token-file", "fd:1"}, wantErr: []string{`use "-" to read from stdin`}},
+		{name: "Stderr descriptor", args: []string{"login", "--token-file", "fd:2"}, wantErr: []string{`use "-" to read from stdin`}},
+		{name: "Help", args: []string{"login", "--help"}, wantOut: "--token-file"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				name := filepath.Join(t.TempDir(), "token")
+				assert.NoError(t, os.WriteFile(name, []byte(tc.file), 0o600))
+				args = append(args[:len(args):len(args)], name)
+			}
+
+			var token string
+			var port int
+
+			root := &Command{Name: "test"}
+			root.AddCommands(&testCommand{
+				cmd: &Command{
+					Name: "login",
+					Flags: Flags{
+						&Flag[string]{Name: "token", Secret: true, Value: &token},
+						&Flag[int]{Name: "port", Secret: true, Value: &port},
+					},
+				},
+			})
+			root.SetInput(strings.NewReader("abc123\n"))
+
+			out, err := execute(root, args...)
+			if tc.wantErr != nil {
+				if !assert.Error(t, err) {
+					return
+				}
+
+				assert.NotContains(t, err.Error(), "abc123")
+
+				for _, want := range tc.wantErr {
+					assert.Contains(t, err.Error(), want)
+				}
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantToken, token)
+			assert.Contains(t, out, tc.wantOut)
+		})
+	}
+
+	t.Run("String", func(t *testing.T) {
+		secret := "abc123"
+		flag := &Flag[string]{Name: "token", Secret: true, Value: &secret}
+		assert.Equal(t, redacted, flag.String())
+		assert.Equal(t, redacted, fmt.Sprint(flag))
+	})
+
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// SpecVersion is the version of the format of Spec. It changes whenever a
+// field is removed or changes meaning; new fields can be added without
+// changing it.
+const SpecVersion = 1
+
+// Spec is a machine-readable description of a command tree, meant for
+// generating wrappers and completions or checking scripts against the CLI.
+type Spec struct {
+	Version int         `json:"version"`
+	Command CommandSpec `json:"command"`
+}
+
+// CommandSpec describes a command.
+type CommandSpec struct {
+	Name     string        `json:"name"`
+	FullName string        `json:"full_name"`
+	Usage    string        `json:"usage"`
+	Desc     string        `json:"desc,omitempty"`
+	LongDesc string        `json:"long_desc,omitempty"`
+	Group    string        `json:"group,omitempty"`
+	Flags    []FlagSpec    `json:"flags"`
+	Args     []ArgSpec     `json:"args"`
+	Commands []CommandSpec `json:"commands"`
+}
+
+// FlagSpec describes a flag. Flags inherited from a parent are only described
+// on the parent, where they're marked as persistent.
+type FlagSpec struct {
+	Name        string `json:"name,omitempty"`
+	Shorthand   string `json:"shorthand,omitempty"`
+	Desc        string `json:"desc,omitempty"`
+	Type        string `json:"type"`
+	Multiple    bool   `json:"multiple,omitempty"`  // the value is a slice
+	Separator   string `json:"separator,omitempty"` // separates values of a slice
+	Default     string `json:"default,omitempty"`
+	DefaultDesc string `json:"default_desc,omitempty"`
+	EnvVar      string `json:"env_var,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Persistent  bool   `json:"persistent,omitempty"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// ArgSpec describes a positional argument. MinCount and MaxCount are how many
+// values it takes, where a MaxCount of -1 means there's no limit.
+type ArgSpec struct {
+	Name     string `json:"name"`
+	Desc     string `json:"desc,omitempty"`
+	Type     string `json:"type"`
+	Required bool   `json:"required,omitempty"`
+	MinCount int    `json:"min_count"`
+	MaxCount int    `json:"max_count"`
+}
+
+// Spec returns the spec of the command and every command below it. Use it on a command from Prepare so flags are in
+// the same order as in help.
+func (c *Command) Spec() Spec {
+	return Spec{
+		Version: SpecVersion,
+		Command: c.commandSpec(),
+	}
+}
+
+// WriteSpec writes the spec of the command to w as indented JSON.
+func (c *Command) WriteSpec(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(c.Spec())
+}
+
+func (c *Command) commandSpec() CommandSpec {
+	spec := CommandSpec{
+		Name:     c.Name,
+		FullName: c.FullName(),
+		Usage:    c.usageLine(),
+		Desc:     formatDesc(c.Desc),
+		LongDesc: c.LongDesc,
+		Group:    c.Group,
+		Flags:    make([]FlagSpec, 0),
+		Args:     make([]ArgSpec, 0),
+		Commands: make([]CommandSpec, 0),
+	}
+
+	for _, flag := range c.localFlags() {
+		spec.Flags = append(spec.Flags, flagSpec(flag.Options()))
+	}
+
+	for _, arg := range c.Args {
+		spec.Args = append(spec.Args, argSpec(arg.Options()))
+	}
+
+	for _, cmd := range c.visibleCommands() {
+		spec.Commands = append(spec.Commands, cmd.commandSpec())
+	}
+
+	return spec
+}
+
+func flagSpec(opt Options) FlagSpec {
+	spec := FlagSpec{
+		Name:        opt.Name,
+		Shorthand:   opt.Shorthand,
+		Desc:        formatDesc(opt.Desc),
+		Type:        opt.Type,
+		Multiple:    opt.IsSlice,
+		Default:     opt.DefaultString,
+		DefaultDesc: opt.DefaultDesc,
+		EnvVar:      envVarName(opt),
+		Group:       opt.Group,
+		Required:    opt.Required,
+		Persistent:  opt.Persistent,
+		Secret:      opt.Secret,
+	}
+
+	if opt.IsSlice && opt.Separator != 0 {
+		spec.Separator = string(opt.Separator)
+	}
+
+	// Help quotes string defaults so empty space is visible, but the spec
+	// has the value as it would be passed on the command line.
+	if opt.Type == "string" && !opt.Secret {
+		if s, err := strconv.Unquote(spec.Default); err == nil {
+			spec.Default = s
+		}
+	}
+
+	return spec
+}
+
+func argSpec(opt Options) ArgSpec {
+	spec := ArgSpec{
+		Name:     opt.Name,
+		Desc:     formatDesc(opt.Desc),
+		Type:     opt.Type,
+		Required: opt.Required,
+		MaxCount: 1,
+	}
+
+	if opt.Required {
+		spec.MinCount = 1
+	}
+
+	if opt.IsSlice {
+		spec.MaxCount = -1
+	}
+
+	return spec
+}
+
+// specCommandName is the name of the command that prints the spec.
+const specCommandName = "__spec"
+
+var _ Runner = (*specCommand)(nil)
+
+// specCommand is a hidden command that prints the spec of the whole command
+// tree as JSON, so tooling can read the spec of the program without linking
+// against it. It's added to root commands with SpecCommand set and named
+// "__spec" so it doesn't clash with real commands.
+type specCommand struct {
+	cmd *Command
+}
+
+// Init returns the command.
+func (s *specCommand) Init() *Command {
+	s.cmd = &Command{
+		Name: specCommandName,
+		Desc: "Print the specification of the CLI as JSON",
+	}
+
+	return s.cmd
+}
+
+// Run prints the spec of the root command.
+func (s *specCommand) Run() error {
+	root := s.cmd
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	// Only the commands on the way to this one have been prepared, so the
+	// rest of the tree is prepared to get the same flags and order as help.
+	if err := root.Visit(func(c *Command) error {
+		return c.prepare()
+	}, VisitDescendants); err != nil {
+		return err
+	}
+
+	return root.WriteSpec(s.cmd.Output())
+}
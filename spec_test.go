@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli/internal/errors"
+)
+
+func TestSpec(t *testing.T) {
+	root := &Command{
+		Name:        "test",
+		Desc:        "a test binary",
+		SpecCommand: true,
+		Flags: Flags{
+			&Flag[bool]{Name: "debug", Desc: "Enable debug logging", Persistent: true},
+			&Flag[string]{Name: "output", Shorthand: "o", Default: "json", EnvVar: EnvVar[string]{Name: "TEST_OUTPUT"}},
+			&Flag[[]string]{Name: "label", Separator: ','},
+		},
+	}
+	root.AddCommands(
+		&testCommand{
+			cmd: &Command{
+				Name: "get",
+				Args: Args{
+					&Arg[string]{Name: "kind", Required: true},
+					&Arg[[]string]{Name: "names"},
+				},
+			},
+		},
+	)
+
+	out, err := execute(root, "__spec")
+	assert.NoError(t, err)
+
+	var spec Spec
+	assert.NoError(t, json.Unmarshal([]byte(out), &spec))
+
+	assert.Equal(t, SpecVersion, spec.Version)
+	assert.Equal(t, "A test binary", spec.Command.Desc)
+	assert.Equal(t, []FlagSpec{
+		{Name: "color", Desc: "When to color output: auto, always or never", Type: "string", Default: "auto", Persistent: true},
+		{Name: "debug", Desc: "Enable debug logging", Type: "bool", Persistent: true},
+		{Name: "help", Shorthand: "h", Desc: "Print help information", Type: "bool", Persistent: true},
+		{Name: "label", Type: "string", Multiple: true, Separator: ","},
+		{Name: "output", Shorthand: "o", Type: "string", Default: "json", EnvVar: "TEST_OUTPUT"},
+	}, spec.Command.Flags)
+
+	// The spec command leaves itself out, but not the help command.
+	assert.Len(t, spec.Command.Commands, 2)
+	assert.Equal(t, "help", spec.Command.Commands[1].Name)
+
+	get := spec.Command.Commands[0]
+	assert.Equal(t, "test get", get.FullName)
+	assert.Empty(t, get.Flags)
+	assert.Equal(t, []ArgSpec{
+		{Name: "kind", Type: "string", Required: true, MinCount: 1, MaxCount: 1},
+		{Name: "names", Type: "string", MinCount: 0, MaxCount: -1},
+	}, get.Args)
+}
+
+func TestSpecCommand(t *testing.T) {
+	root := &Command{
+		Name:        "test",
+		SpecCommand: true,
+		Args: Args{
+			&Arg[string]{Name: "name"},
+		},
+	}
+
+	out, err := execute(root, "__spec")
+	assert.NoError(t, err)
+
+	var spec Spec
+	assert.NoError(t, json.Unmarshal([]byte(out), &spec))
+	assert.Empty(t, spec.Command.Commands)
+
+	// The command is hidden, so a root without other commands doesn't look
+	// like it has any.
+	out, err = execute(&Command{Name: "test", SpecCommand: true}, "--help")
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "[command]")
+	assert.NotContains(t, out, "__spec")
+
+	// Without SpecCommand there's no __spec command.
+	_, err = execute(&Command{Name: "test"}, "__spec")
+	assert.True(t, errors.As(err, &ErrUnknown{}))
+}
@@ -3,6 +3,8 @@ package tablewriter
 import (
 	"strings"
 	"unicode/utf8"
+
+	"github.com/rdeusser/cli/internal/wrap"
 )
 
 const (
@@ -10,11 +12,17 @@ const (
 	escapeStopRune  = 'm'
 )
 
+// minWrapWidth is the narrowest the last column is wrapped to, no matter how
+// little room is left for it.
+const minWrapWidth = 20
+
 // Writer represents a grid of rows and columns.
 type Writer struct {
-	sb    strings.Builder
-	lines [][]Cell
-	err   error
+	sb       strings.Builder
+	lines    [][]Cell
+	maxWidth int
+	noColor  bool
+	err      error
 }
 
 // NewWriter returns an initialized writer.
@@ -46,6 +54,20 @@ func (w *Writer) AddLine(cells ...Cell) {
 	}
 }
 
+// SetColor turns colors on or off. When they're off, ANSI-style escape
+// sequences in cells are left out of the rendered table, so text that was
+// colored before it was added is plain. Colors are on by default.
+func (w *Writer) SetColor(enabled bool) {
+	w.noColor = !enabled
+}
+
+// SetMaxWidth sets the width the table should fit in. Text in the last column
+// that would go past it is wrapped onto new lines that are indented to line up
+// with the column. A width of 0 turns wrapping off.
+func (w *Writer) SetMaxWidth(width int) {
+	w.maxWidth = width
+}
+
 // MustRender panics if rendering returns an error.
 func (w *Writer) MustRender() string {
 	s, err := w.Render()
@@ -68,10 +90,13 @@ func (w *Writer) Render() (string, error) {
 
 			switch cell.Align {
 			case AlignLeft:
-				w.write(cell.Text)
-				if !isLast(line, j) {
-					w.writePadding(padding)
+				if isLast(line, j) {
+					w.write(w.wrap(line, cell))
+					continue
 				}
+
+				w.write(cell.Text)
+				w.writePadding(padding)
 			case AlignCenter:
 				// TODO(rdeusser)
 			case AlignRight:
@@ -88,6 +113,30 @@ func (w *Writer) Render() (string, error) {
 	return w.sb.String(), nil
 }
 
+// wrap returns the text of the last cell in line wrapped to fit in the max
+// width of the table.
+func (w *Writer) wrap(line []Cell, cell Cell) string {
+	if w.maxWidth <= 0 {
+		return cell.Text
+	}
+
+	start := 0
+	for j := 0; j < len(line)-1; j++ {
+		start += maxColumnWidth(w.lines, j)
+	}
+
+	available := w.maxWidth - start
+	if available < minWrapWidth {
+		available = minWrapWidth
+	}
+
+	if countRunes(strings.TrimRight(cell.Text, " ")) <= available {
+		return cell.Text
+	}
+
+	return strings.Join(wrap.Wrap(cell.Text, available), "\n"+indent(start))
+}
+
 // padding returns the number of spaces needed to pad a cell.
 func (w *Writer) padding(cell Cell, line int) int {
 	maxLen := maxColumnWidth(w.lines, line)
@@ -106,6 +155,10 @@ func (w *Writer) writePadding(padding int) {
 
 // write writes the provided string to an underlying buffer.
 func (w *Writer) write(s string) {
+	if w.noColor {
+		s = StripSequences(s)
+	}
+
 	w.sb.WriteString(s)
 }
 
@@ -137,6 +190,30 @@ func countRunes(s string) int {
 	return utf8.RuneCountInString(text)
 }
 
+// StripSequences returns s without ANSI-style escape sequences.
+func StripSequences(s string) string {
+	var sb strings.Builder
+
+	for {
+		start := strings.IndexRune(s, escapeStartRune)
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexRune(s[start:], escapeStopRune)
+		if end < 0 {
+			break
+		}
+
+		sb.WriteString(s[:start])
+		s = s[start+end+1:]
+	}
+
+	sb.WriteString(s)
+
+	return sb.String()
+}
+
 // stripEscapeSequences strips ANSI-style escape sequences from the string. This
 // is used to get the width of a string for determining the widest cell in a
 // column.
@@ -106,3 +106,24 @@ func TestRenderWithIndent(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, want, s)
 }
+
+func TestRenderWithMaxWidth(t *testing.T) {
+	writer := NewWriter()
+	writer.SetMaxWidth(40)
+	writer.AddLine(
+		Cell{
+			Text:    "--debug",
+			Padding: 4,
+		},
+		Cell{
+			Text: "Set the logging level to debug for every command",
+		},
+	)
+
+	want := `--debug    Set the logging level to
+           debug for every command`
+
+	s, err := writer.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, want, s)
+}
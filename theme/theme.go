@@ -0,0 +1,112 @@
+// Package theme contains the styles used to color help and error output.
+//
+// Set a theme on the root command to change how its output looks:
+//
+//	t := theme.Default()
+//	t.Header = theme.Style{Foreground: "#ff8700", Bold: true}
+//	t.Command = theme.Style{Foreground: "39"}
+//
+//	root := &cli.Command{
+//	    Name:  "myapp",
+//	    Theme: &t,
+//	}
+package theme
+
+import (
+	"fmt"
+
+	"github.com/rdeusser/cli/internal/termenv"
+)
+
+// Style is how a kind of text looks.
+//
+// Colors are ANSI colors ("0" to "15"), 256 colors ("16" to "255") or true
+// colors ("#rrggbb"). They're converted to the closest color the terminal
+// supports, so a true color still looks right on a terminal with 16 colors.
+// An empty color leaves the terminal's default.
+type Style struct {
+	Foreground string
+	Background string
+	Bold       bool
+	Faint      bool
+	Italic     bool
+	Underline  bool
+}
+
+// Render returns s in the style if stdout is a terminal that should be
+// colored. Use Paint with Command.ColorEnabled to follow the --color flag of a
+// command.
+func (s Style) Render(format string, a ...any) string {
+	text := fmt.Sprintf(format, a...)
+	if !termenv.Enabled() {
+		return text
+	}
+
+	return s.Paint(text)
+}
+
+// Paint returns text in the style, whether or not colors are on. It's for
+// callers that make their own decision (e.g. a help builder writing to a
+// file).
+func (s Style) Paint(text string) string {
+	return termenv.Style(text, termenv.Attrs{
+		Foreground: s.Foreground,
+		Background: s.Background,
+		Bold:       s.Bold,
+		Faint:      s.Faint,
+		Italic:     s.Italic,
+		Underline:  s.Underline,
+	})
+}
+
+// Theme is the styles of help and error output.
+type Theme struct {
+	// Header is the style of section headers in help (e.g. "USAGE:").
+	Header Style
+
+	// Command is the style of the names of commands and help topics.
+	Command Style
+
+	// Flag is the style of the names of flags and arguments.
+	Flag Style
+
+	// Placeholder is the style of the placeholder of a flag's value (e.g.
+	// "<string>").
+	Placeholder Style
+
+	// Code is the style of examples and code spans in descriptions.
+	Code Style
+
+	// Required is the style of the "(required)" note of a flag.
+	Required Style
+
+	// Error is the style of the "error: " prefix of errors and of short
+	// errors.
+	Error Style
+
+	// Warning is the style of the "warning: " prefix of warnings (e.g. an
+	// unknown argument).
+	Warning Style
+
+	// Message is the style of the message after an "error: " or "warning: "
+	// prefix.
+	Message Style
+
+	// Hint is the style of hints on what to do next (e.g. the line telling you
+	// how to get more help).
+	Hint Style
+}
+
+// Default returns the theme used when a command doesn't set one.
+func Default() Theme {
+	return Theme{
+		Header:   Style{Foreground: "3"},
+		Command:  Style{Foreground: "2"},
+		Flag:     Style{Foreground: "2"},
+		Code:     Style{Foreground: "2"},
+		Required: Style{Foreground: "3"},
+		Error:    Style{Foreground: "1"},
+		Warning:  Style{Foreground: "3"},
+		Message:  Style{Foreground: "15", Bold: true},
+	}
+}
@@ -0,0 +1,36 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStylePaint(t *testing.T) {
+	testCases := []struct {
+		name      string
+		colorterm string
+		term      string
+		style     Style
+		want      string
+	}{
+		{"ANSI", "", "xterm", Style{Foreground: "2"}, "\x1b[32mtext\x1b[0m"},
+		{"Attributes", "", "xterm", Style{Foreground: "1", Bold: true, Underline: true}, "\x1b[31;1;4mtext\x1b[0m"},
+		{"Background", "", "xterm", Style{Background: "4"}, "\x1b[44mtext\x1b[0m"},
+		{"256 colors", "", "xterm-256color", Style{Foreground: "208"}, "\x1b[38;5;208mtext\x1b[0m"},
+		{"256 colors on ANSI", "", "xterm", Style{Foreground: "196"}, "\x1b[91mtext\x1b[0m"},
+		{"True color", "truecolor", "xterm", Style{Foreground: "#ff8700"}, "\x1b[38;2;255;135;0mtext\x1b[0m"},
+		{"True color on 256 colors", "", "xterm-256color", Style{Foreground: "#ff8700"}, "\x1b[38;5;208mtext\x1b[0m"},
+		{"Empty", "", "xterm", Style{}, "text"},
+		{"Invalid color", "", "xterm", Style{Foreground: "green"}, "text"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("COLORTERM", tc.colorterm)
+			t.Setenv("TERM", tc.term)
+
+			assert.Equal(t, tc.want, tc.style.Paint("text"))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts time.Time values are parsed with when a
+// flag or argument doesn't set any.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// unixMillisThreshold is the smallest bare unix timestamp that's treated as
+// milliseconds instead of seconds. As seconds it's in the year 5138, so there's
+// no real ambiguity.
+const unixMillisThreshold = 100_000_000_000
+
+// timeOptions controls how time.Time values are parsed.
+type timeOptions struct {
+	layouts  []string
+	location *time.Location
+	now      func() time.Time
+}
+
+// timeLayouts returns layout followed by layouts, skipping layout if it's
+// empty.
+func timeLayouts(layout string, layouts []string) []string {
+	if layout == "" {
+		return layouts
+	}
+
+	return append([]string{layout}, layouts...)
+}
+
+// parseTimeValue parses s as a time. The following are tried in order:
+//
+//   - Each layout (DefaultTimeLayouts if there aren't any).
+//   - A unix timestamp (e.g. 1700000000). A bare number is in seconds, unless
+//     it's at least 100000000000 (in the year 5138 as seconds), which makes it
+//     milliseconds. The unit can be made explicit with an "@" (e.g.
+//     @1700000000 is always seconds and @1700000000123ms milliseconds).
+//   - now, today, yesterday or tomorrow.
+//   - A duration relative to now. Durations are in the past unless they start
+//     with "+" (e.g. 2h, 90m, 3d, 1w, -2h or +30m). The units d (days) and w
+//     (weeks) are supported on top of the ones time.ParseDuration knows about.
+//
+// Layouts without a time zone, keywords and unix timestamps use the location
+// from opts (time.Local if not set).
+func parseTimeValue(s string, opts timeOptions) (time.Time, error) {
+	loc := opts.location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	now := time.Now
+	if opts.now != nil {
+		now = opts.now
+	}
+
+	layouts := opts.layouts
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, ok := parseUnixTime(s); ok {
+		return t.In(loc), nil
+	}
+
+	current := now().In(loc)
+	midnight := time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, loc)
+
+	switch strings.ToLower(s) {
+	case "now":
+		return current, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	}
+
+	if d, err := parseRelativeDuration(s); err == nil {
+		return current.Add(d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time (expected one of the layouts %s, a unix timestamp like 1700000000, a keyword like yesterday, or a duration like 2h)", s, strings.Join(layouts, ", "))
+}
+
+// parseUnixTime parses a unix timestamp. A bare number is in seconds or
+// milliseconds depending on its size (see unixMillisThreshold), while
+// @1700000000 is always seconds and @1700000000123ms always milliseconds.
+func parseUnixTime(s string) (time.Time, bool) {
+	if !strings.HasPrefix(s, "@") {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		if i >= unixMillisThreshold || i <= -unixMillisThreshold {
+			return time.UnixMilli(i), true
+		}
+
+		return time.Unix(i, 0), true
+	}
+
+	s = strings.TrimPrefix(s, "@")
+
+	if strings.HasSuffix(s, "ms") {
+		i, err := strconv.ParseInt(strings.TrimSuffix(s, "ms"), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.UnixMilli(i), true
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(i, 0), true
+}
+
+// parseRelativeDuration parses a duration that's relative to now. Durations
+// without a sign are in the past.
+func parseRelativeDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(s, " ago"))
+
+	sign := time.Duration(-1)
+	switch {
+	case strings.HasPrefix(s, "+"):
+		sign = 1
+		s = s[1:]
+	case strings.HasPrefix(s, "-"):
+		s = s[1:]
+	}
+
+	var days time.Duration
+
+	// time.ParseDuration doesn't know about days or weeks, so those are
+	// pulled off the front first (e.g. 1w2d3h).
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+	} {
+		idx := strings.Index(s, unit.suffix)
+		if idx <= 0 {
+			continue
+		}
+
+		n, err := strconv.Atoi(s[:idx])
+		if err != nil {
+			return 0, err
+		}
+
+		days += time.Duration(n) * unit.size
+		s = s[idx+1:]
+	}
+
+	var d time.Duration
+	if s != "" {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+
+		d = parsed
+	}
+
+	return sign * (days + d), nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTime(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	now := time.Date(2023, time.November, 14, 22, 13, 20, 0, loc)
+
+	testCases := []struct {
+		name    string
+		value   string
+		layouts []string
+		want    time.Time
+	}{
+		{"RFC 3339", "2023-11-14T10:00:00Z", nil, time.Date(2023, time.November, 14, 10, 0, 0, 0, time.UTC)},
+		{"Date in location", "2023-11-14", nil, time.Date(2023, time.November, 14, 0, 0, 0, 0, loc)},
+		{"Custom layout", "14/11/2023", []string{"02/01/2006"}, time.Date(2023, time.November, 14, 0, 0, 0, 0, loc)},
+		{"Unix seconds", "1700000000", nil, time.Unix(1700000000, 0)},
+		{"Unix millis", "1700000000123", nil, time.UnixMilli(1700000000123)},
+		{"Explicit unix seconds", "@1700000000", nil, time.Unix(1700000000, 0)},
+		{"Explicit unix millis", "@1700000000123ms", nil, time.UnixMilli(1700000000123)},
+		{"Explicit seconds past the threshold", "@100000000000", nil, time.Unix(100000000000, 0)},
+		{"Epoch", "0", nil, time.Unix(0, 0)},
+		{"Unix before 1970", "@-86400", nil, time.Unix(-86400, 0)},
+		{"Number in a layout", "20231114", []string{"20060102"}, time.Date(2023, time.November, 14, 0, 0, 0, 0, loc)},
+		{"Relative", "2h", nil, now.Add(-2 * time.Hour)},
+		{"Relative days", "3d", nil, now.AddDate(0, 0, -3)},
+		{"Relative future", "+1w", nil, now.AddDate(0, 0, 7)},
+		{"Yesterday", "yesterday", nil, time.Date(2023, time.November, 13, 0, 0, 0, 0, loc)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var v time.Time
+
+			flag := &Flag[time.Time]{
+				Name:     "since",
+				Layouts:  tc.layouts,
+				Location: loc,
+				Now:      func() time.Time { return now },
+				Value:    &v,
+			}
+
+			assert.NoError(t, flag.Init())
+			assert.NoError(t, flag.Set(tc.value))
+			assert.True(t, tc.want.Equal(v), "want %s, got %s", tc.want, v)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range []string{"not a time", "@abc", "@1700000000s"} {
+			_, err := parseValue[time.Time](s, parseOptions{})
+			assert.Error(t, err, s)
+		}
+	})
+
+	t.Run("Environment variable", func(t *testing.T) {
+		t.Setenv("CLI_TEST_SINCE", "2023-11-14 10:00")
+
+		envLoc := time.FixedZone("UTC+2", 2*60*60)
+
+		testCases := []struct {
+			name string
+			env  EnvVar[time.Time]
+			want time.Time
+		}{
+			{"Flag location", EnvVar[time.Time]{Name: "CLI_TEST_SINCE"}, time.Date(2023, time.November, 14, 10, 0, 0, 0, loc)},
+			{"Own location", EnvVar[time.Time]{Name: "CLI_TEST_SINCE", Location: envLoc}, time.Date(2023, time.November, 14, 10, 0, 0, 0, envLoc)},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				var v time.Time
+
+				flag := &Flag[time.Time]{Name: "since", Location: loc, EnvVar: tc.env, Value: &v}
+				assert.NoError(t, flag.Init())
+				assert.True(t, tc.want.Equal(v), "want %s, got %s", tc.want, v)
+			})
+		}
+
+		env := EnvVar[time.Time]{Name: "CLI_TEST_SINCE", Location: envLoc}
+		v, err := env.Lookup()
+		assert.NoError(t, err)
+		assert.True(t, time.Date(2023, time.November, 14, 10, 0, 0, 0, envLoc).Equal(v), "got %s", v)
+	})
+}
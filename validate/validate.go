@@ -0,0 +1,125 @@
+// package validate contains common validators for flags and arguments.
+//
+// Each validator returns a function that can be assigned to the Validate field
+// of a flag or argument:
+//
+//	&cli.Flag[int]{
+//	    Name:     "port",
+//	    Validate: validate.Range(1, 65535),
+//	}
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/rdeusser/cli/constraints"
+)
+
+// Func validates a value of type T.
+type Func[T any] func(T) error
+
+// All returns a validator that runs each validator in order and returns the
+// first error.
+func All[T any](fns ...Func[T]) Func[T] {
+	return func(v T) error {
+		for _, fn := range fns {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Each returns a validator that runs fn against every element of a slice.
+func Each[E any](fn Func[E]) Func[[]E] {
+	return func(values []E) error {
+		for _, v := range values {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Min returns a validator that checks a number is at least min.
+func Min[T constraints.Number](min T) Func[T] {
+	return func(v T) error {
+		if v < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+
+		return nil
+	}
+}
+
+// Max returns a validator that checks a number is at most max.
+func Max[T constraints.Number](max T) Func[T] {
+	return func(v T) error {
+		if v > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+
+		return nil
+	}
+}
+
+// Range returns a validator that checks a number is between min and max
+// (inclusive).
+func Range[T constraints.Number](min, max T) Func[T] {
+	return func(v T) error {
+		if v < min || v > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+
+		return nil
+	}
+}
+
+// Pattern returns a validator that checks a string matches the regular
+// expression expr. It panics if expr can't be compiled.
+func Pattern(expr string) Func[string] {
+	re := regexp.MustCompile(expr)
+
+	return func(v string) error {
+		if !re.MatchString(v) {
+			return fmt.Errorf("must match pattern %q", expr)
+		}
+
+		return nil
+	}
+}
+
+// Length returns a validator that checks the number of characters in a string
+// is between min and max (inclusive). A max of 0 means there is no upper bound.
+func Length(min, max int) Func[string] {
+	return func(v string) error {
+		n := utf8.RuneCountInString(v)
+
+		if n < min {
+			return fmt.Errorf("must be at least %d characters", min)
+		}
+
+		if max > 0 && n > max {
+			return fmt.Errorf("must be at most %d characters", max)
+		}
+
+		return nil
+	}
+}
+
+// NotEmpty returns a validator that checks a slice has at least one element.
+func NotEmpty[T ~[]E, E any]() Func[T] {
+	return func(v T) error {
+		if len(v) == 0 {
+			return fmt.Errorf("must not be empty")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rdeusser/cli"
+)
+
+func TestValidators(t *testing.T) {
+	testCases := []struct {
+		name    string
+		fn      func() error
+		wantErr string
+	}{
+		{"min ok", func() error { return Min(1)(1) }, ""},
+		{"min fails", func() error { return Min(1)(0) }, "must be at least 1"},
+		{"max fails", func() error { return Max(1.5)(2) }, "must be at most 1.5"},
+		{"range fails", func() error { return Range[uint16](1, 10)(11) }, "must be between 1 and 10"},
+		{"pattern ok", func() error { return Pattern("^[a-z]+$")("abc") }, ""},
+		{"pattern fails", func() error { return Pattern("^[a-z]+$")("ABC") }, `must match pattern "^[a-z]+$"`},
+		{"length too short", func() error { return Length(2, 0)("a") }, "must be at least 2 characters"},
+		{"length too long", func() error { return Length(0, 2)("abc") }, "must be at most 2 characters"},
+		{"not empty fails", func() error { return NotEmpty[[]string]()(nil) }, "must not be empty"},
+		{"each fails", func() error { return Each(Min(1))([]int{1, 0}) }, "must be at least 1"},
+		{"all fails", func() error { return All(Min(1), Max(5))(6) }, "must be at most 5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestFlagValidate(t *testing.T) {
+	var port int
+
+	flag := &cli.Flag[int]{
+		Name:     "port",
+		Value:    &port,
+		Validate: Range(1, 65535),
+	}
+
+	assert.NoError(t, flag.Init())
+	assert.NoError(t, flag.Set("8080"))
+	assert.Equal(t, 8080, port)
+
+	assert.EqualError(t, flag.Set("0"), "must be between 1 and 65535")
+	assert.Equal(t, 8080, port)
+}
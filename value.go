@@ -2,15 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"net/url"
-	"os"
-	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rdeusser/cli/constraints"
-	"github.com/rdeusser/cli/internal/errors"
 )
 
 //go:generate go run tools/gen-type-converter/main.go
@@ -60,60 +60,27 @@ import (
 // way to write that, but it's a function in a comment so I'm not putting a lot
 // of effort into it.
 type Value interface {
-	constraints.Bool | constraints.Signed | constraints.Unsigned | constraints.Float | constraints.Complex | constraints.Bytes | constraints.String | constraints.Time | constraints.URL | constraints.IP | Path
+	constraints.Bool | constraints.Signed | constraints.Unsigned | constraints.Float | constraints.Complex | constraints.Bytes | constraints.String | constraints.Time | constraints.URL | constraints.IP | constraints.Big | Path | []Path | InputFile | OutputFile
 }
 
-var _ fmt.Stringer = (*Path)(nil)
-
-// Path is a path on the filesystem.
-type Path struct {
-	Path   string
-	Ext    string
-	IsDir  bool
-	Exists bool
-}
-
-// ParsePath takes a path as input, cleans it, and returns Path.
-func ParsePath(path string) (Path, error) {
-	p := Path{
-		Path:   filepath.Clean(path),
-		Ext:    filepath.Ext(path),
-		IsDir:  false,
-		Exists: false,
-	}
-
-	file, err := os.Open(p.Path)
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return p, err
-	}
-	defer file.Close()
-
-	// File doesn't exist, so return early.
-	if file == nil {
-		return p, nil
-	}
-
-	info, err := os.Stat(file.Name())
-	if err != nil {
-		return p, err
-	}
-
-	p.IsDir = info.IsDir()
-	p.Exists = true
-
-	return p, nil
-}
-
-// String returns the path provided to Path.
-func (p Path) String() string {
-	return p.Path
+// bigFloatPrec is the precision big.Float values are parsed with. It's large
+// enough to hold any float64 exactly and then some.
+const bigFloatPrec = 256
+
+// parseOptions controls how a value is parsed. Most options only apply to
+// specific types.
+type parseOptions struct {
+	separator byte
+	octal     bool
+	time      timeOptions
+	path      pathOptions
 }
 
 // parseValue parses any input value whose string form can be parsed as one of
 // the above types.
 //
 // See comments on the `Value` type to understand this.
-func parseValue[T Value](value any, separator byte, layout string) (T, error) {
+func parseValue[T Value](value any, opts parseOptions) (T, error) {
 	var result T
 
 	s := fmt.Sprint(value)
@@ -123,133 +90,157 @@ func parseValue[T Value](value any, separator byte, layout string) (T, error) {
 
 	switch any(&result).(type) {
 	case *bool, *[]bool:
-		v, err := parseBool[T](s, separator)
+		v, err := parseBool[T](s, opts.separator)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *int, *[]int:
-		v, err := parseSigned[T](s, separator, 32)
+		v, err := parseSigned[T](s, opts.separator, strconv.IntSize, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *int8, *[]int8:
-		v, err := parseSigned[T](s, separator, 8)
+		v, err := parseSigned[T](s, opts.separator, 8, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *int16, *[]int16:
-		v, err := parseSigned[T](s, separator, 16)
+		v, err := parseSigned[T](s, opts.separator, 16, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *int32, *[]int32:
-		v, err := parseSigned[T](s, separator, 32)
+		v, err := parseSigned[T](s, opts.separator, 32, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *int64, *[]int64:
-		v, err := parseSigned[T](s, separator, 64)
+		v, err := parseSigned[T](s, opts.separator, 64, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *uint, *[]uint:
-		v, err := parseUnsigned[T](s, separator, 32)
+		v, err := parseUnsigned[T](s, opts.separator, strconv.IntSize, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
-	case *uint8, *[]uint8:
-		v, err := parseUnsigned[T](s, separator, 8)
+	case *uint8:
+		v, err := parseUnsigned[T](s, opts.separator, 8, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *uint16, *[]uint16:
-		v, err := parseUnsigned[T](s, separator, 16)
+		v, err := parseUnsigned[T](s, opts.separator, 16, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *uint32, *[]uint32:
-		v, err := parseUnsigned[T](s, separator, 32)
+		v, err := parseUnsigned[T](s, opts.separator, 32, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *uint64, *[]uint64:
-		v, err := parseUnsigned[T](s, separator, 64)
+		v, err := parseUnsigned[T](s, opts.separator, 64, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *uintptr, *[]uintptr:
-		v, err := parseUnsigned[T](s, separator, 64)
+		v, err := parseUnsigned[T](s, opts.separator, strconv.IntSize, opts.octal)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *float32, *[]float32:
-		v, err := parseFloat[T](s, separator, 32)
+		v, err := parseFloat[T](s, opts.separator, 32)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *float64, *[]float64:
-		v, err := parseFloat[T](s, separator, 64)
+		v, err := parseFloat[T](s, opts.separator, 64)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *complex64, *[]complex64:
-		v, err := parseComplex[T](s, separator, 64)
+		v, err := parseComplex[T](s, opts.separator, 64)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *complex128, *[]complex128:
-		v, err := parseComplex[T](s, separator, 128)
+		v, err := parseComplex[T](s, opts.separator, 128)
 		if err != nil {
 			return result, err
 		}
 		result = v
-	case *[][]byte:
-		v, err := parseBytes[T]([]byte(s), separator)
+	case *[]byte, *[][]byte:
+		v, err := parseBytes[T]([]byte(s), opts.separator)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *string, *[]string:
-		v, err := parseString[T](s, separator)
+		v, err := parseString[T](s, opts.separator)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *time.Time, *[]time.Time:
-		v, err := parseTime[T](s, separator, layout)
+		v, err := parseTime[T](s, opts.separator, opts.time)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *url.URL, *[]url.URL:
-		v, err := parseURL[T](s, separator)
+		v, err := parseURL[T](s, opts.separator)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *net.IP, *[]net.IP:
-		v, err := parseIP[T](s, separator)
+		v, err := parseIP[T](s, opts.separator)
+		if err != nil {
+			return result, err
+		}
+		result = v
+	case **big.Int, *[]*big.Int:
+		v, err := parseBigInt[T](s, opts.separator, opts.octal)
+		if err != nil {
+			return result, err
+		}
+		result = v
+	case **big.Float, *[]*big.Float:
+		v, err := parseBigFloat[T](s, opts.separator)
 		if err != nil {
 			return result, err
 		}
 		result = v
 	case *Path, *[]Path:
-		v, err := parsePath[T](s, separator)
+		v, err := parsePath[T](s, opts.separator, opts.path)
+		if err != nil {
+			return result, err
+		}
+		result = v
+	case *InputFile:
+		v, err := parseInputFile[T](s)
+		if err != nil {
+			return result, err
+		}
+		result = v
+	case *OutputFile:
+		v, err := parseOutputFile[T](s)
 		if err != nil {
 			return result, err
 		}
@@ -263,7 +254,10 @@ func parseBool[T Value](s string, separator byte) (T, error) {
 	var result T
 
 	values := make([]bool, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
 		b, err := strconv.ParseBool(v)
@@ -286,14 +280,17 @@ func parseBool[T Value](s string, separator byte) (T, error) {
 	return result, nil
 }
 
-func parseSigned[T Value](s string, separator byte, bitSize int) (T, error) {
+func parseSigned[T Value](s string, separator byte, bitSize int, octal bool) (T, error) {
 	var result T
 
 	values := make([]int64, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
-		i, err := strconv.ParseInt(v, 10, bitSize)
+		i, err := strconv.ParseInt(normalizeInt(v, octal), 0, bitSize)
 		if err != nil {
 			return result, err
 		}
@@ -329,14 +326,17 @@ func parseSigned[T Value](s string, separator byte, bitSize int) (T, error) {
 	return result, nil
 }
 
-func parseUnsigned[T Value](s string, separator byte, bitSize int) (T, error) {
+func parseUnsigned[T Value](s string, separator byte, bitSize int, octal bool) (T, error) {
 	var result T
 
 	values := make([]uint64, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
-		i, err := strconv.ParseUint(v, 10, bitSize)
+		i, err := strconv.ParseUint(normalizeInt(v, octal), 0, bitSize)
 		if err != nil {
 			return result, err
 		}
@@ -359,8 +359,6 @@ func parseUnsigned[T Value](s string, separator byte, bitSize int) (T, error) {
 		*v = convertUint64ToUintptr(values)[0]
 	case *[]uint:
 		*v = convertUint64ToUint(values)
-	case *[]uint8:
-		*v = convertUint64ToUint8(values)
 	case *[]uint16:
 		*v = convertUint64ToUint16(values)
 	case *[]uint32:
@@ -376,11 +374,44 @@ func parseUnsigned[T Value](s string, separator byte, bitSize int) (T, error) {
 	return result, nil
 }
 
+func parseBigInt[T Value](s string, separator byte, octal bool) (T, error) {
+	var result T
+
+	values := make([]*big.Int, 0)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
+
+	for _, v := range slice {
+		i, ok := new(big.Int).SetString(normalizeInt(v, octal), 0)
+		if !ok {
+			return result, fmt.Errorf("invalid integer %q", v)
+		}
+
+		values = append(values, i)
+	}
+
+	switch v := any(&result).(type) {
+	case **big.Int:
+		*v = values[0]
+	case *[]*big.Int:
+		*v = values
+	default:
+		return result, fmt.Errorf("expected type to be constrained by constraints.Big, got %T", result)
+	}
+
+	return result, nil
+}
+
 func parseFloat[T Value](s string, separator byte, bitSize int) (T, error) {
 	var result T
 
 	values := make([]float64, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
 		b, err := strconv.ParseFloat(v, bitSize)
@@ -407,11 +438,44 @@ func parseFloat[T Value](s string, separator byte, bitSize int) (T, error) {
 	return result, nil
 }
 
+func parseBigFloat[T Value](s string, separator byte) (T, error) {
+	var result T
+
+	values := make([]*big.Float, 0)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
+
+	for _, v := range slice {
+		f, _, err := big.ParseFloat(v, 0, bigFloatPrec, big.ToNearestEven)
+		if err != nil {
+			return result, err
+		}
+
+		values = append(values, f)
+	}
+
+	switch v := any(&result).(type) {
+	case **big.Float:
+		*v = values[0]
+	case *[]*big.Float:
+		*v = values
+	default:
+		return result, fmt.Errorf("expected type to be constrained by constraints.Big, got %T", result)
+	}
+
+	return result, nil
+}
+
 func parseComplex[T Value](s string, separator byte, bitSize int) (T, error) {
 	var result T
 
 	values := make([]complex128, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
 		b, err := strconv.ParseComplex(v, bitSize)
@@ -442,7 +506,10 @@ func parseBytes[T Value](s []byte, separator byte) (T, error) {
 	var result T
 
 	values := make([][]byte, 0)
-	slice := splitBytes(s, separator)
+	slice, err := splitBytes(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	values = append(values, slice...)
 
@@ -462,7 +529,10 @@ func parseString[T Value](s string, separator byte) (T, error) {
 	var result T
 
 	values := make([]string, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	values = append(values, slice...)
 
@@ -478,14 +548,17 @@ func parseString[T Value](s string, separator byte) (T, error) {
 	return result, nil
 }
 
-func parseTime[T Value](s string, separator byte, layout string) (T, error) {
+func parseTime[T Value](s string, separator byte, opts timeOptions) (T, error) {
 	var result T
 
 	values := make([]time.Time, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
-		t, err := time.Parse(layout, v)
+		t, err := parseTimeValue(v, opts)
 		if err != nil {
 			return result, err
 		}
@@ -509,7 +582,10 @@ func parseURL[T Value](s string, separator byte) (T, error) {
 	var result T
 
 	values := make([]url.URL, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
 		u, err := url.Parse(v)
@@ -536,7 +612,10 @@ func parseIP[T Value](s string, separator byte) (T, error) {
 	var result T
 
 	values := make([]net.IP, 0)
-	slice := splitString(s, separator)
+	slice, err := splitString(s, separator)
+	if err != nil {
+		return result, err
+	}
 
 	for _, v := range slice {
 		values = append(values, net.ParseIP(v))
@@ -554,31 +633,77 @@ func parseIP[T Value](s string, separator byte) (T, error) {
 	return result, nil
 }
 
-func parsePath[T Value](s string, separator byte) (T, error) {
-	var result T
+// formatValue returns the string form of value that parses back to the same
+// value. Values that hold many values are joined with separator.
+func formatValue[T Value](value T, separator byte, opts timeOptions) string {
+	if !isSliceValue[T]() {
+		return formatElement(value, opts)
+	}
 
-	values := make([]Path, 0)
-	slice := splitString(s, separator)
+	rv := reflect.ValueOf(value)
+	values := make([]string, 0, rv.Len())
 
-	for _, v := range slice {
-		p, err := ParsePath(v)
-		if err != nil {
-			return result, err
+	for i := 0; i < rv.Len(); i++ {
+		values = append(values, formatElement(rv.Index(i).Interface(), opts))
+	}
+
+	return joinValues(values, separator)
+}
+
+// formatElement returns the string form of a single value.
+func formatElement(value any, opts timeOptions) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+
+		layout := time.RFC3339Nano
+		if len(opts.layouts) > 0 {
+			layout = opts.layouts[0]
 		}
 
-		values = append(values, p)
+		return v.Format(layout)
+	case url.URL:
+		return v.String()
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		if reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+			return ""
+		}
+
+		return v.String()
 	}
 
-	switch v := any(&result).(type) {
-	case *Path:
-		*v = values[0]
-	case *[]Path:
-		*v = values
-	default:
-		return result, fmt.Errorf("expected type to be Path, got %T", result)
+	return fmt.Sprint(value)
+}
+
+// normalizeInt prepares s to be parsed as an integer literal with base 0. A
+// leading 0 without a base prefix (e.g. 0755) would be parsed as octal, so
+// unless octal is true the leading zeros are dropped and the value is parsed as
+// decimal instead.
+func normalizeInt(s string, octal bool) string {
+	sign, digits := "", s
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, digits = s[:1], s[1:]
 	}
 
-	return result, nil
+	if octal || len(digits) < 2 || digits[0] != '0' {
+		return s
+	}
+
+	switch digits[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return s
+	}
+
+	digits = strings.TrimLeft(digits, "0_")
+	if digits == "" {
+		digits = "0"
+	}
+
+	return sign + digits
 }
 
 func isZeroValue[T any](value T) bool {
@@ -621,8 +746,16 @@ func isZeroValue[T any](value T) bool {
 		return v.Equal(*new(time.Time))
 	case url.URL:
 		return v == *new(url.URL)
+	case *big.Int:
+		return v == nil
+	case *big.Float:
+		return v == nil
 	case Path:
 		return v == *new(Path)
+	case InputFile:
+		return v.Name == ""
+	case OutputFile:
+		return v.Name == ""
 	}
 
 	if trimBrackets(value) == "" {
@@ -631,3 +764,63 @@ func isZeroValue[T any](value T) bool {
 
 	return false
 }
+
+// isSliceValue returns true if T holds many values. net.IP and []byte are
+// technically slices, but they're single values as far as flags and arguments
+// are concerned.
+func isSliceValue[T Value]() bool {
+	switch any(new(T)).(type) {
+	case *net.IP, *[]byte:
+		return false
+	}
+
+	return reflect.TypeOf(new(T)).Elem().Kind() == reflect.Slice
+}
+
+// typeNames are the names of types that aren't named after their kind.
+var typeNames = map[reflect.Type]string{
+	reflect.TypeOf(time.Duration(0)): "duration",
+	reflect.TypeOf(time.Time{}):      "time",
+	reflect.TypeOf(url.URL{}):        "url",
+	reflect.TypeOf(net.IP{}):         "ip",
+	reflect.TypeOf([]byte{}):         "bytes",
+	reflect.TypeOf(&big.Int{}):       "int",
+	reflect.TypeOf(&big.Float{}):     "float",
+	reflect.TypeOf(Path{}):           "path",
+	reflect.TypeOf(InputFile{}):      "file",
+	reflect.TypeOf(OutputFile{}):     "file",
+}
+
+// typeName returns a short name for T that's shown in help (e.g. "string").
+// Slices are named after their elements.
+func typeName[T Value]() string {
+	t := reflect.TypeOf(new(T)).Elem()
+	if isSliceValue[T]() {
+		t = t.Elem()
+	}
+
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Complex64, reflect.Complex128:
+		return "complex"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes"
+		}
+	}
+
+	return "value"
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegers(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		octal bool
+		want  int64
+	}{
+		{"Decimal", "42", false, 42},
+		{"Hex", "0xff", false, 255},
+		{"Binary", "0b101", false, 5},
+		{"Octal prefix", "0o755", false, 493},
+		{"Leading zero is decimal", "0755", false, 755},
+		{"Leading zero is octal", "0755", true, 493},
+		{"Underscores", "1_000_000", false, 1000000},
+		{"Negative hex", "-0x10", false, -16},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := parseValue[int64](tc.value, parseOptions{octal: tc.octal})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, v)
+		})
+	}
+
+	t.Run("Platform int size", func(t *testing.T) {
+		v, err := parseValue[int]("4294967296", parseOptions{})
+		if strconv.IntSize == 64 {
+			assert.NoError(t, err)
+			assert.Equal(t, int64(4294967296), int64(v))
+		} else {
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Big int", func(t *testing.T) {
+		var n *big.Int
+
+		_, err := execute(&Command{
+			Name: "test",
+			Flags: Flags{
+				&Flag[*big.Int]{Name: "modulus", Value: &n},
+			},
+		}, "--modulus", "0xffffffffffffffffffffffffffffffff")
+		assert.NoError(t, err)
+
+		want, _ := new(big.Int).SetString("ffffffffffffffffffffffffffffffff", 16)
+		assert.Equal(t, 0, want.Cmp(n))
+	})
+
+	t.Run("Big float", func(t *testing.T) {
+		v, err := parseValue[*big.Float]("1.5e100", parseOptions{})
+		assert.NoError(t, err)
+
+		want, _, _ := big.ParseFloat("1.5e100", 10, bigFloatPrec, big.ToNearestEven)
+		assert.Equal(t, 0, want.Cmp(v))
+	})
+}
+
+func TestBytes(t *testing.T) {
+	var key, data []byte
+
+	_, err := execute(&Command{
+		Name: "test",
+		Flags: Flags{
+			&Flag[[]byte]{Name: "key", Value: &key, Default: []byte("default")},
+		},
+		Args: Args{
+			&Arg[[]byte]{Name: "data", Value: &data},
+		},
+	}, "--key", "secret", "payload")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key)
+	assert.Equal(t, []byte("payload"), data)
+
+	flag := &Flag[[]byte]{Name: "key", Value: &key}
+	assert.False(t, flag.Options().IsSlice)
+}